@@ -1,102 +1,183 @@
 package ya360
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"strings"
+	"time"
 )
 
-func (ya *Ya360) get(uri url.URL, resp interface{}) (int, error) {
+func (ya *Ya360) get(ctx context.Context, uri url.URL, resp interface{}) error {
+	return ya.do(ctx, http.MethodGet, uri, nil, "", resp)
+}
 
-	u := ya.s.URL + uri.String()
+func (ya *Ya360) alter(ctx context.Context, method string, uri url.URL, req interface{}, resp interface{}) error {
 
-	// Create request
-	req, err := http.NewRequest(http.MethodGet, u, nil)
-	if err != nil {
-		return 0, fmt.Errorf("can't create new request: %v", err)
+	var body []byte
+
+	if req != nil {
+		s, err := json.Marshal(req)
+		if err != nil {
+			return errorWrap(0, 0, err)
+		}
+		body = s
 	}
 
-	// Set Authorization header
-	req.Header.Add("Authorization", fmt.Sprintf("OAuth %s", ya.s.OAuth))
+	return ya.do(ctx, method, uri, body, "application/json", resp)
+}
 
-	// Make request
-	res, err := http.DefaultClient.Do(req)
+// upload sends binary data with specified content type. Data is read
+// into memory at once to be able to resend it on retries
+func (ya *Ya360) upload(ctx context.Context, method string, uri url.URL, data io.Reader, contentType string, resp interface{}) error {
+
+	body, err := io.ReadAll(data)
 	if err != nil {
-		return 0, err
+		return errorWrap(0, 0, fmt.Errorf("can't read upload data: %v", err))
 	}
-	defer res.Body.Close()
 
-	dJ := json.NewDecoder(res.Body)
+	return ya.do(ctx, method, uri, body, contentType, resp)
+}
+
+// do makes request and retries it in accordance with the retry policy
+func (ya *Ya360) do(ctx context.Context, method string, uri url.URL, body []byte, contentType string, resp interface{}) error {
+
+	u := ya.s.URL + uri.String()
+
+	attempts := ya.s.Retry.attempts(method)
+	refreshed := false
 
-	e := errorRx{}
-	if res.StatusCode != http.StatusOK {
-		if err := dJ.Decode(&e); err != nil {
-			return res.StatusCode, err
+	for attempt := 1; ; attempt++ {
+
+		if err := ya.l.wait(ctx); err != nil {
+			return errorWrap(0, attempt-1, err)
 		}
-		return res.StatusCode, fmt.Errorf("wrong status code: %s", e.Message)
-	}
 
-	if resp != nil {
-		// Decode response
-		if err := dJ.Decode(&resp); err != nil {
-			return res.StatusCode, fmt.Errorf("can't decode response body: %v", err)
+		token, err := ya.t.Token(ctx)
+		if err != nil {
+			return errorWrap(0, attempt-1, err)
 		}
-	}
 
-	return res.StatusCode, nil
-}
+		req, err := ya.newRequest(ctx, method, u, token, body, contentType)
+		if err != nil {
+			return errorWrap(0, attempt, err)
+		}
 
-func (ya *Ya360) alter(method string, uri url.URL, req interface{}, resp interface{}) (int, error) {
+		status, ra, err := ya.send(req, resp)
+		if err == nil {
+			return nil
+		}
 
-	var rdr io.Reader
+		// Retry once with refreshed token if the current one is rejected
+		if status == http.StatusUnauthorized && refreshed == false {
+			if r, ok := ya.t.(TokenRefresher); ok {
+				refreshed = true
+				if t, err := r.RefreshToken(ctx, token); err == nil && t != token {
+					continue
+				}
+			}
+		}
 
-	u := ya.s.URL + uri.String()
+		if attempt >= attempts || retryable(status) == false || ctx.Err() != nil {
+			return errorWrap(status, attempt, err)
+		}
 
-	if req != nil {
-		s, err := json.Marshal(req)
-		if err != nil {
-			return 0, err
+		t := time.NewTimer(ya.s.Retry.backoff(attempt, ra))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return errorWrap(0, attempt, ctx.Err())
+		case <-t.C:
 		}
-		rdr = strings.NewReader(string(s))
-	} else {
-		rdr = nil
+	}
+}
+
+// newRequest creates a new request for a single attempt
+func (ya *Ya360) newRequest(ctx context.Context, method, u, token string, body []byte, contentType string) (*http.Request, error) {
+
+	var rdr io.Reader
+
+	if body != nil {
+		rdr = bytes.NewReader(body)
 	}
 
-	r, err := http.NewRequest(method, u, rdr)
+	// Create request
+	req, err := http.NewRequestWithContext(ctx, method, u, rdr)
 	if err != nil {
-		return 0, err
+		return nil, fmt.Errorf("can't create new request: %v", err)
 	}
 
-	// Set Authorization header
-	r.Header.Add("Content-Type", "application/json")
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", ya.s.OAuth))
+	// Set headers
+	if len(contentType) > 0 {
+		req.Header.Add("Content-Type", contentType)
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("OAuth %s", token))
+
+	return req, nil
+}
+
+// send makes a single request attempt. Besides the status code it returns
+// delay specified by server in `Retry-After` header
+func (ya *Ya360) send(req *http.Request, resp interface{}) (int, time.Duration, error) {
 
 	// Make request
-	res, err := http.DefaultClient.Do(r)
+	res, err := ya.c.Do(req)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	defer res.Body.Close()
 
-	dJ := json.NewDecoder(res.Body)
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, 0, fmt.Errorf("can't read response body: %v", err)
+	}
+
+	empty := len(bytes.TrimSpace(body)) == 0
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
 
-	e := errorRx{}
-	if res.StatusCode != http.StatusOK {
-		if err := dJ.Decode(&e); err != nil {
-			return res.StatusCode, err
+		e := Error{
+			Code:      res.StatusCode,
+			Text:      fmt.Sprintf("wrong status code: %s", http.StatusText(res.StatusCode)),
+			RequestID: res.Header.Get("X-Request-Id"),
+			Body:      bodyTruncate(body),
 		}
-		return res.StatusCode, fmt.Errorf("wrong status code: %s", e.Message)
+
+		// Error body may be empty or not a JSON (e.g. proxy error page)
+		eRx := errorRx{}
+		if empty == false && json.Unmarshal(body, &eRx) == nil && len(eRx.Message) > 0 {
+			e.Text = fmt.Sprintf("wrong status code: %s", eRx.Message)
+			e.APICode = eRx.Code
+			e.Message = eRx.Message
+			e.Details = eRx.Details
+		}
+
+		return res.StatusCode, retryAfter(res.Header.Get("Retry-After")), e
 	}
 
-	if resp != nil {
+	if resp != nil && empty == false {
 		// Decode response
-		if err := dJ.Decode(&resp); err != nil {
-			return res.StatusCode, fmt.Errorf("can't decode response body: %v", err)
+		if err := json.Unmarshal(body, resp); err != nil {
+			return res.StatusCode, 0, Error{
+				Code:      res.StatusCode,
+				Text:      fmt.Sprintf("can't decode response body: %v", err),
+				RequestID: res.Header.Get("X-Request-Id"),
+				Body:      bodyTruncate(body),
+				err:       err,
+			}
 		}
 	}
 
-	return res.StatusCode, nil
+	return res.StatusCode, 0, nil
+}
+
+// bodyTruncate returns response body truncated to be kept in errors
+func bodyTruncate(body []byte) string {
+	if len(body) > errorBodyMaxLen {
+		return string(body[:errorBodyMaxLen]) + "..."
+	}
+	return string(body)
 }
@@ -0,0 +1,481 @@
+package ya360
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testAPIInit(t *testing.T, h http.HandlerFunc, s Settings) Ya360 {
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	s.URL = srv.URL
+	s.OAuth = "test"
+	s.OrgID = 1
+
+	return Init(s)
+}
+
+// testRoundTripper responds to requests without network and counts them
+type testRoundTripper struct {
+	calls int32
+}
+
+func (rt *testRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+
+	atomic.AddInt32(&rt.calls, 1)
+
+	w := httptest.NewRecorder()
+	w.Write([]byte(`{"id":"1","nickname":"test"}`))
+
+	resp := w.Result()
+	resp.Request = r
+
+	return resp, nil
+}
+
+func TestAPITransport(t *testing.T) {
+
+	for _, c := range []struct {
+		name string
+		s    func(rt http.RoundTripper) Settings
+	}{
+		{"transport", func(rt http.RoundTripper) Settings {
+			return Settings{Transport: rt}
+		}},
+		{"http client", func(rt http.RoundTripper) Settings {
+			return Settings{HTTPClient: &http.Client{Transport: rt}}
+		}},
+	} {
+
+		rt := &testRoundTripper{}
+
+		s := c.s(rt)
+		s.URL = "http://ya360.invalid"
+		s.OAuth = "test"
+		s.OrgID = 1
+
+		y := Init(s)
+
+		u, err := y.UserGet(context.Background(), "1")
+		if err != nil {
+			t.Fatalf("Transport error: %s: %v", c.name, err)
+		}
+
+		if u.ID != "1" || atomic.LoadInt32(&rt.calls) != 1 {
+			t.Fatalf("Transport error: %s: request is not sent via injected round tripper (returned: %s, calls: %d)", c.name, u.ID, rt.calls)
+		}
+	}
+
+	t.Logf("Transport: success")
+}
+
+func TestAPIRetry(t *testing.T) {
+
+	var calls int32
+
+	y := testAPIInit(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"code":14,"message":"unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"1","nickname":"test"}`))
+	}, Settings{
+		Retry: RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+		},
+	})
+
+	u, err := y.UserGet(context.Background(), "1")
+	if err != nil {
+		t.Fatal("Retry error:", err)
+	}
+
+	if u.ID != "1" || atomic.LoadInt32(&calls) != 3 {
+		t.Fatalf("Retry error: incorrect result (returned: %s, calls: %d)", u.ID, calls)
+	}
+
+	t.Logf("Retry: success")
+}
+
+func TestAPIRetryNonIdempotent(t *testing.T) {
+
+	var calls int32
+
+	y := testAPIInit(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"code":8,"message":"too many requests"}`))
+	}, Settings{
+		Retry: RetryPolicy{
+			MaxAttempts: 2,
+			MinBackoff:  time.Millisecond,
+		},
+	})
+
+	if _, err := y.UserUpdate(context.Background(), "1", UserUpdateTx{}); err == nil {
+		t.Fatal("Retry non-idempotent error: error expected")
+	}
+
+	if c := atomic.LoadInt32(&calls); c != 1 {
+		t.Fatalf("Retry non-idempotent error: request must not be retried by default (calls: %d)", c)
+	}
+
+	y.s.Retry.RetryNonIdempotent = true
+
+	_, err := y.UserUpdate(context.Background(), "1", UserUpdateTx{})
+	if err == nil || strings.Contains(err.Error(), "attempts: 2") == false {
+		t.Fatal("Retry non-idempotent error: attempts count expected in error:", err)
+	}
+
+	t.Logf("Retry non-idempotent: success")
+}
+
+func TestAPIRetryAfterMaxBackoff(t *testing.T) {
+
+	var calls int32
+
+	y := testAPIInit(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 2 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"code":8,"message":"too many requests"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"1"}`))
+	}, Settings{
+		Retry: RetryPolicy{
+			MaxAttempts: 2,
+			MaxBackoff:  10 * time.Millisecond,
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := y.UserGet(ctx, "1"); err != nil {
+		t.Fatal("Retry after max backoff error: delay must be limited by max backoff:", err)
+	}
+
+	if c := atomic.LoadInt32(&calls); c != 2 {
+		t.Fatalf("Retry after max backoff error: incorrect calls count (calls: %d)", c)
+	}
+
+	t.Logf("Retry after max backoff: success")
+}
+
+func TestAPIRateLimit(t *testing.T) {
+
+	y := testAPIInit(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"1"}`))
+	}, Settings{
+		RateLimit: RateLimit{
+			RPS:   20,
+			Burst: 1,
+		},
+	})
+
+	start := time.Now()
+
+	done := make(chan error)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, err := y.UserGet(context.Background(), "1")
+			done <- err
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		if err := <-done; err != nil {
+			t.Fatal("Rate limit error:", err)
+		}
+	}
+
+	if d := time.Since(start); d < 90*time.Millisecond {
+		t.Fatalf("Rate limit error: requests made too fast (%s)", d)
+	}
+
+	if d := y.RateLimitWait(); d <= 0 || d > 50*time.Millisecond {
+		t.Fatalf("Rate limit error: incorrect wait time (%s)", d)
+	}
+
+	t.Logf("Rate limit: success")
+}
+
+func TestAPIPagination(t *testing.T) {
+
+	y := testAPIInit(t, func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		w.Write([]byte(`{"users":[{"id":"` + page + `-1"},{"id":"` + page + `-2"}],"page":` + page + `,"pages":5,"perPage":2,"total":10}`))
+	}, Settings{})
+
+	for _, workers := range []int{1, 3} {
+
+		users, err := y.UsersAll(context.Background(), 2, ListOptions{}, workers)
+		if err != nil {
+			t.Fatal("Pagination error:", err)
+		}
+
+		if len(users) != 10 || users[0].ID != "1-1" || users[9].ID != "5-2" {
+			t.Fatalf("Pagination error: incorrect users (workers: %d, returned: %v)", workers, users)
+		}
+	}
+
+	it := y.UsersIter(context.Background(), 2, ListOptions{})
+
+	c := 0
+	for it.Next() {
+		c++
+	}
+	if it.Err() != nil || c != 10 || it.Page() != 5 {
+		t.Fatalf("Pagination error: incorrect iteration (users: %d, page: %d, error: %v)", c, it.Page(), it.Err())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := y.UsersPages(ctx, 2, ListOptions{}, 2, func(page int64, users UsersRx, err error) error {
+		cancel()
+		return nil
+	})
+	if err != context.Canceled {
+		t.Fatal("Pagination error: context cancel expected:", err)
+	}
+
+	t.Logf("Pagination: success")
+}
+
+func TestAPIError(t *testing.T) {
+
+	y := testAPIInit(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "test-request")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":5,"message":"user not found","details":[{"@type":"type.googleapis.com/google.rpc.ErrorInfo","reason":"NOT_FOUND"}]}`))
+	}, Settings{})
+
+	_, err := y.UserGet(context.Background(), "1")
+	if err == nil {
+		t.Fatal("Error error: error expected")
+	}
+
+	if IsNotFound(err) == false || IsConflict(err) || IsRateLimited(err) || IsUnauthorized(err) {
+		t.Fatal("Error error: incorrect error kind:", err)
+	}
+
+	var e Error
+	if errors.As(err, &e) == false {
+		t.Fatal("Error error: incorrect error type:", err)
+	}
+
+	if e.Code != http.StatusNotFound || e.APICode != APICodeNotFound || e.Message != "user not found" || e.RequestID != "test-request" {
+		t.Fatalf("Error error: incorrect error fields (returned: %#v)", e)
+	}
+
+	if len(e.Details) != 1 || e.Details[0].Type != "type.googleapis.com/google.rpc.ErrorInfo" || e.Details[0].Fields["reason"] != "NOT_FOUND" {
+		t.Fatalf("Error error: incorrect error details (returned: %#v)", e.Details)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := y.UserGet(ctx, "1"); errors.Is(err, context.Canceled) == false {
+		t.Fatal("Error error: context cancel expected:", err)
+	}
+
+	t.Logf("Error: success")
+}
+
+func TestAPINonJSONBody(t *testing.T) {
+
+	y := testAPIInit(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("<html><body>502 Bad Gateway</body></html>"))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}, Settings{
+		Retry: RetryPolicy{
+			MaxAttempts: 1,
+		},
+	})
+
+	_, err := y.UserGet(context.Background(), "1")
+
+	var e Error
+	if errors.As(err, &e) == false || e.Code != http.StatusBadGateway || strings.Contains(e.Body, "502 Bad Gateway") == false {
+		t.Fatal("Non-JSON body error: incorrect error:", err)
+	}
+
+	if _, err := y.UserDelete(context.Background(), "1"); err != nil {
+		t.Fatal("Non-JSON body error: empty 2xx response must succeed:", err)
+	}
+
+	if _, err := y.UserUpdate(context.Background(), "1", UserUpdateTx{}); IsUnauthorized(err) == false {
+		t.Fatal("Non-JSON body error: unauthorized error expected:", err)
+	}
+
+	t.Logf("Non-JSON body: success")
+}
+
+func TestAPIListOptions(t *testing.T) {
+
+	var query string
+
+	y := testAPIInit(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"page":1,"pages":1}`))
+	}, Settings{})
+
+	parentID := int64(0)
+
+	for _, c := range []struct {
+		opts  ListOptions
+		query string
+	}{
+		{ListOptions{}, "page=1&perPage=10"},
+		{ListOptions{ParentID: &parentID}, "page=1&parentId=0&perPage=10"},
+		{ListOptions{OrderBy: OrderByName, Direction: DirectionDesc, ExternalID: "ext"}, "direction=desc&externalId=ext&orderBy=name&page=1&perPage=10"},
+	} {
+		if _, err := y.DepartmentsList(context.Background(), 1, 10, c.opts); err != nil {
+			t.Fatal("List options error:", err)
+		}
+		if query != c.query {
+			t.Fatalf("List options error: incorrect query (expected: %s, returned: %s)", c.query, query)
+		}
+	}
+
+	// Parent department filter is not sent for users and groups
+	for _, l := range []func() error{
+		func() error {
+			_, err := y.UsersList(context.Background(), 1, 10, ListOptions{ParentID: &parentID})
+			return err
+		},
+		func() error {
+			_, err := y.GroupsList(context.Background(), 1, 10, ListOptions{ParentID: &parentID})
+			return err
+		},
+	} {
+		if err := l(); err != nil {
+			t.Fatal("List options error:", err)
+		}
+		if query != "page=1&perPage=10" {
+			t.Fatalf("List options error: parent ID must not be sent (returned: %s)", query)
+		}
+	}
+
+	t.Logf("List options: success")
+}
+
+func TestAPIUserGetByExternalID(t *testing.T) {
+
+	var calls int32
+
+	y := testAPIInit(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Query().Get("externalId") != "ext" {
+			t.Errorf("User get by external ID error: filter is not sent (query: %s)", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"users":[{"id":"1","externalId":"ext"}],"page":1,"pages":1,"perPage":1,"total":1}`))
+	}, Settings{})
+
+	u, err := y.UserGetByExternalID(context.Background(), "ext")
+	if err != nil {
+		t.Fatal("User get by external ID error:", err)
+	}
+
+	if u.ID != "1" || atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("User get by external ID error: incorrect user or requests count (returned: %s, %d)", u.ID, calls)
+	}
+
+	t.Logf("User get by external ID: success")
+}
+
+func TestAPIUsers2FAListPartial(t *testing.T) {
+
+	y := testAPIInit(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users/2/2fa"):
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":5,"message":"user not found"}`))
+		case strings.HasSuffix(r.URL.Path, "/2fa"):
+			w.Write([]byte(`{"userId":"` + strings.Split(r.URL.Path, "/")[6] + `","has2fa":true}`))
+		default:
+			w.Write([]byte(`{"users":[{"id":"1"},{"id":"2"},{"id":"3"}],"page":1,"pages":1,"perPage":10,"total":3}`))
+		}
+	}, Settings{
+		Retry: RetryPolicy{
+			MaxAttempts: 1,
+		},
+	})
+
+	u, err := y.Users2FAList(context.Background(), 10, 2)
+	if err == nil || IsNotFound(err) == false {
+		t.Fatal("Users 2FA list partial error: not found error expected, got:", err)
+	}
+
+	if len(u.Users) != 2 || u.Users[0].UserID != "1" || u.Users[1].UserID != "3" {
+		t.Fatalf("Users 2FA list partial error: incorrect statuses (returned: %v)", u.Users)
+	}
+
+	if len(u.Failed) != 1 || u.Failed[0].UserID != "2" || IsNotFound(u.Failed[0].Err) == false {
+		t.Fatalf("Users 2FA list partial error: incorrect failures (returned: %v)", u.Failed)
+	}
+
+	t.Logf("Users 2FA list partial: success")
+}
+
+func TestAPIUserLookupConcurrentMiss(t *testing.T) {
+
+	var loads int32
+
+	y := testAPIInit(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&loads, 1)
+		w.Write([]byte(`{"users":[{"id":"1","nickname":"known"}],"page":1,"pages":1,"perPage":1000,"total":1}`))
+	}, Settings{})
+
+	// Warm the cache and make it old enough to be reloaded
+	if _, err := y.UserGetByNickname(context.Background(), "known"); err != nil {
+		t.Fatal("User lookup concurrent miss error:", err)
+	}
+	y.uc.updated = time.Now().Add(-usersCacheRefreshMinAge)
+
+	done := make(chan error)
+	for i := 0; i < 10; i++ {
+		go func() {
+			_, err := y.UserGetByNickname(context.Background(), "missing")
+			done <- err
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		if err := <-done; IsNotFound(err) == false {
+			t.Fatal("User lookup concurrent miss error: not found error expected, got:", err)
+		}
+	}
+
+	if l := atomic.LoadInt32(&loads); l != 2 {
+		t.Fatalf("User lookup concurrent miss error: cache must be reloaded once (loads: %d)", l)
+	}
+
+	// Just reloaded cache is not reloaded on a miss
+	if _, err := y.UserGetByNickname(context.Background(), "missing"); IsNotFound(err) == false {
+		t.Fatal("User lookup concurrent miss error: not found error expected, got:", err)
+	}
+
+	if l := atomic.LoadInt32(&loads); l != 2 {
+		t.Fatalf("User lookup concurrent miss error: fresh cache must not be reloaded (loads: %d)", l)
+	}
+
+	t.Logf("User lookup concurrent miss: success")
+}
@@ -1,6 +1,7 @@
 package ya360
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -53,14 +54,15 @@ type DepartmentCreateTx struct {
 	ParentID    int64  `json:"parentId"`
 }
 
-// DepartmentUpdateTx contains data to update department
+// DepartmentUpdateTx contains data to update department.
+// Nil fields are not changed, see `OptString` for details
 type DepartmentUpdateTx struct {
-	Description string `json:"description,omitempty"`
-	ExternalID  string `json:"externalId,omitempty"`
-	HeadID      string `json:"headId,omitempty"`
-	Label       string `json:"label,omitempty"`
-	Name        string `json:"name,omitempty"`
-	ParentID    int64  `json:"parentId,omitempty"`
+	Description *OptString `json:"description,omitempty"`
+	ExternalID  *OptString `json:"externalId,omitempty"`
+	HeadID      *OptString `json:"headId,omitempty"`
+	Label       *OptString `json:"label,omitempty"`
+	Name        *OptString `json:"name,omitempty"`
+	ParentID    *OptInt64  `json:"parentId,omitempty"`
 }
 
 // DepartmentAliasAddTx contains data to add new alias for department
@@ -70,7 +72,7 @@ type DepartmentAliasAddTx struct {
 
 // DepartmentCreate creates new department
 // Link: https://yandex.ru/dev/api360/doc/ref/DepartmentService/DepartmentService_Create.html
-func (ya *Ya360) DepartmentCreate(department DepartmentCreateTx) (DepartmentRx, error) {
+func (ya *Ya360) DepartmentCreate(ctx context.Context, department DepartmentCreateTx) (DepartmentRx, error) {
 
 	var (
 		resp DepartmentRx
@@ -83,12 +85,8 @@ func (ya *Ya360) DepartmentCreate(department DepartmentCreateTx) (DepartmentRx,
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodPost, ur, department, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.alter(ctx, http.MethodPost, ur, department, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
@@ -96,7 +94,7 @@ func (ya *Ya360) DepartmentCreate(department DepartmentCreateTx) (DepartmentRx,
 
 // DepartmentGet gets specified department
 // Link: https://yandex.ru/dev/api360/doc/ref/DepartmentService/DepartmentService_Get.html
-func (ya *Ya360) DepartmentGet(departmentID int64) (DepartmentRx, error) {
+func (ya *Ya360) DepartmentGet(ctx context.Context, departmentID int64) (DepartmentRx, error) {
 
 	var (
 		resp DepartmentRx
@@ -109,12 +107,8 @@ func (ya *Ya360) DepartmentGet(departmentID int64) (DepartmentRx, error) {
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.get(ur, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.get(ctx, ur, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
@@ -122,7 +116,7 @@ func (ya *Ya360) DepartmentGet(departmentID int64) (DepartmentRx, error) {
 
 // DepartmentsList gets departments list
 // Link: https://yandex.ru/dev/api360/doc/ref/DepartmentService/DepartmentService_List.html
-func (ya *Ya360) DepartmentsList(page, perPage, parentId int64, orderBy Order) (DepartmentsRx, error) {
+func (ya *Ya360) DepartmentsList(ctx context.Context, page, perPage int64, opts ListOptions) (DepartmentsRx, error) {
 
 	var (
 		resp DepartmentsRx
@@ -132,28 +126,140 @@ func (ya *Ya360) DepartmentsList(page, perPage, parentId int64, orderBy Order) (
 
 	urlParams.Add("page", strconv.FormatInt(page, 10))
 	urlParams.Add("perPage", strconv.FormatInt(perPage, 10))
-	urlParams.Add("parentId", strconv.FormatInt(parentId, 10))
-	urlParams.Add("orderBy", orderBy.String())
+	if opts.ParentID != nil {
+		urlParams.Add("parentId", strconv.FormatInt(*opts.ParentID, 10))
+	}
+	opts.urlParams(urlParams)
 
 	ur := url.URL{
 		Path:     fmt.Sprintf("/directory/v1/org/%d/departments", ya.s.OrgID),
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.get(ur, &resp)
+	if err := ya.get(ctx, ur, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// DepartmentsPages walks through all departments list pages and calls `fn` for each of them.
+// Page fetch errors are passed into `fn`. Walking stops if `fn` returns an error
+// or context is done. If `workers` is greater than 1 pages after the first one are
+// fetched concurrently and `fn` may be called out of the pages order (but never concurrently)
+func (ya *Ya360) DepartmentsPages(ctx context.Context, perPage int64, opts ListOptions, workers int, fn func(page int64, departments DepartmentsRx, err error) error) error {
+	return pagesWalk(ctx, workers, func(ctx context.Context, page int64) (int64, func() error) {
+		l, err := ya.DepartmentsList(ctx, page, perPage, opts)
+		return l.Pages, func() error {
+			return fn(page, l, err)
+		}
+	})
+}
+
+// DepartmentsAll gets all departments from all pages of departments list
+func (ya *Ya360) DepartmentsAll(ctx context.Context, perPage int64, opts ListOptions, workers int) ([]DepartmentRx, error) {
+
+	pages := make(map[int64][]DepartmentRx)
+
+	err := ya.DepartmentsPages(ctx, perPage, opts, workers, func(page int64, departments DepartmentsRx, err error) error {
+		if err != nil {
+			return err
+		}
+		pages[page] = departments.Departments
+		return nil
+	})
 	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
+		return nil, err
+	}
+
+	departments := []DepartmentRx{}
+	for page := int64(1); page <= int64(len(pages)); page++ {
+		departments = append(departments, pages[page]...)
+	}
+
+	return departments, nil
+}
+
+// DepartmentsIterator iterates over departments lazily fetching departments list pages one by one
+type DepartmentsIterator struct {
+	ya      *Ya360
+	ctx     context.Context
+	perPage int64
+	opts    ListOptions
+
+	page        int64
+	pages       int64
+	departments []DepartmentRx
+	i           int
+	department  DepartmentRx
+	err         error
+}
+
+// DepartmentsIter creates iterator over all departments
+func (ya *Ya360) DepartmentsIter(ctx context.Context, perPage int64, opts ListOptions) *DepartmentsIterator {
+	return &DepartmentsIterator{
+		ya:      ya,
+		ctx:     ctx,
+		perPage: perPage,
+		opts:    opts,
+	}
+}
+
+// Next advances iterator to the next department. It returns false when
+// all departments have been iterated or an error occurred
+func (it *DepartmentsIterator) Next() bool {
+
+	for it.i >= len(it.departments) {
+
+		if it.err != nil || (it.page > 0 && it.page >= it.pages) {
+			return false
+		}
+
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		l, err := it.ya.DepartmentsList(it.ctx, it.page+1, it.perPage, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page++
+		it.pages = l.Pages
+		it.departments = l.Departments
+		it.i = 0
+
+		if len(it.departments) == 0 {
+			return false
 		}
 	}
 
-	return resp, nil
+	it.department = it.departments[it.i]
+	it.i++
+
+	return true
+}
+
+// Department returns current department
+func (it *DepartmentsIterator) Department() DepartmentRx {
+	return it.department
+}
+
+// Page returns number of the last fetched page
+func (it *DepartmentsIterator) Page() int64 {
+	return it.page
+}
+
+// Err returns error occurred during iteration
+func (it *DepartmentsIterator) Err() error {
+	return it.err
 }
 
 // DepartmentUpdate updates specified department with new `department` data
 // Link: https://yandex.ru/dev/api360/doc/ref/DepartmentService/DepartmentService_Update.html
-func (ya *Ya360) DepartmentUpdate(departmentID int64, department DepartmentUpdateTx) (DepartmentRx, error) {
+func (ya *Ya360) DepartmentUpdate(ctx context.Context, departmentID int64, department DepartmentUpdateTx) (DepartmentRx, error) {
 
 	var (
 		resp DepartmentRx
@@ -166,12 +272,8 @@ func (ya *Ya360) DepartmentUpdate(departmentID int64, department DepartmentUpdat
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodPatch, ur, department, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.alter(ctx, http.MethodPatch, ur, department, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
@@ -179,7 +281,7 @@ func (ya *Ya360) DepartmentUpdate(departmentID int64, department DepartmentUpdat
 
 // DepartmentAliasAdd adds new alias to specified department
 // Link: https://yandex.ru/dev/api360/doc/ref/DepartmentService/DepartmentService_CreateAlias.html
-func (ya *Ya360) DepartmentAliasAdd(departmentID int64, alias DepartmentAliasAddTx) (DepartmentRx, error) {
+func (ya *Ya360) DepartmentAliasAdd(ctx context.Context, departmentID int64, alias DepartmentAliasAddTx) (DepartmentRx, error) {
 
 	var (
 		resp DepartmentRx
@@ -192,12 +294,8 @@ func (ya *Ya360) DepartmentAliasAdd(departmentID int64, alias DepartmentAliasAdd
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodPost, ur, alias, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.alter(ctx, http.MethodPost, ur, alias, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
@@ -205,7 +303,7 @@ func (ya *Ya360) DepartmentAliasAdd(departmentID int64, alias DepartmentAliasAdd
 
 // DepartmentAliasDelete deletes alias from specified department
 // Link: https://yandex.ru/dev/api360/doc/ref/DepartmentService/DepartmentService_DeleteAlias.html
-func (ya *Ya360) DepartmentAliasDelete(departmentID int64, alias string) (DepartmentAliasDeleteRx, error) {
+func (ya *Ya360) DepartmentAliasDelete(ctx context.Context, departmentID int64, alias string) (DepartmentAliasDeleteRx, error) {
 
 	var (
 		resp DepartmentAliasDeleteRx
@@ -218,12 +316,8 @@ func (ya *Ya360) DepartmentAliasDelete(departmentID int64, alias string) (Depart
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodDelete, ur, nil, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.alter(ctx, http.MethodDelete, ur, nil, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
@@ -231,7 +325,7 @@ func (ya *Ya360) DepartmentAliasDelete(departmentID int64, alias string) (Depart
 
 // DepartmentDelete deletes department
 // Link: https://yandex.ru/dev/api360/doc/ref/DepartmentService/DepartmentService_Delete.html
-func (ya *Ya360) DepartmentDelete(departmentID int64) (DepartmentDeleteRx, error) {
+func (ya *Ya360) DepartmentDelete(ctx context.Context, departmentID int64) (DepartmentDeleteRx, error) {
 
 	var (
 		resp DepartmentDeleteRx
@@ -244,12 +338,8 @@ func (ya *Ya360) DepartmentDelete(departmentID int64) (DepartmentDeleteRx, error
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodDelete, ur, nil, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.alter(ctx, http.MethodDelete, ur, nil, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
@@ -0,0 +1,318 @@
+package ya360
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// DepartmentMoveTx contains data to move department
+type DepartmentMoveTx struct {
+
+	// ParentID is an ID of the new parent department
+	ParentID int64
+
+	// Name is a new name of the department. If not set, name is not changed
+	Name string
+
+	// DryRun enables to only check the operation and report steps to be done
+	DryRun bool
+}
+
+// DepartmentMergeTx contains data to merge departments
+type DepartmentMergeTx struct {
+
+	// DryRun enables to only check the operation and report steps to be done
+	DryRun bool
+}
+
+// DepartmentDeleteRecursiveTx contains data to delete department with all its descendants
+type DepartmentDeleteRecursiveTx struct {
+
+	// UsersDepartmentID is an ID of department to move users of the deleted
+	// departments to. If not set, parent of the deleted department is used
+	UsersDepartmentID int64
+
+	// DryRun enables to only check the operation and report steps to be done
+	DryRun bool
+}
+
+// DepartmentOpsRx contains report of the department operation steps
+type DepartmentOpsRx struct {
+	DryRun bool
+	Steps  []DepartmentOpsStep
+}
+
+// DepartmentOpsStep contains a single department operation step.
+// Target is an ID of the user or department the step is applied to,
+// DestinationID is an ID of the department user or department is moved to
+type DepartmentOpsStep struct {
+	Action        DepartmentOpsAction
+	Target        string
+	DestinationID int64
+	Err           error
+}
+
+type DepartmentOpsAction string
+
+const (
+	DepartmentOpsActionMove     DepartmentOpsAction = "department_move"
+	DepartmentOpsActionDelete   DepartmentOpsAction = "department_delete"
+	DepartmentOpsActionUserMove DepartmentOpsAction = "user_move"
+)
+
+func (a DepartmentOpsAction) String() string {
+	return string(a)
+}
+
+// departmentOps executes department operation steps and records them into the report
+type departmentOps struct {
+	rx  DepartmentOpsRx
+	err error
+}
+
+// DepartmentMove moves department under the new parent and optionally renames it.
+// Moving department into itself or its descendants is rejected
+func (ya *Ya360) DepartmentMove(ctx context.Context, departmentID int64, move DepartmentMoveTx) (DepartmentOpsRx, error) {
+
+	ops := departmentOps{
+		rx: DepartmentOpsRx{
+			DryRun: move.DryRun,
+		},
+	}
+
+	tree, err := ya.DepartmentsTree(ctx, 1)
+	if err != nil {
+		return ops.rx, err
+	}
+
+	n, err := departmentOpsNode(tree, departmentID)
+	if err != nil {
+		return ops.rx, err
+	}
+
+	p, err := departmentOpsNode(tree, move.ParentID)
+	if err != nil {
+		return ops.rx, err
+	}
+
+	if n.Parent == nil {
+		return ops.rx, fmt.Errorf("root department %d can't be moved", departmentID)
+	}
+
+	if departmentOpsInSubtree(n, p) {
+		return ops.rx, fmt.Errorf("department %d can't be moved into its own subtree", departmentID)
+	}
+
+	u := DepartmentUpdateTx{
+		ParentID: SetInt64(move.ParentID),
+	}
+	if len(move.Name) > 0 {
+		u.Name = SetString(move.Name)
+	}
+
+	ops.step(DepartmentOpsActionMove, strconv.FormatInt(departmentID, 10), move.ParentID, func() error {
+		_, err := ya.DepartmentUpdate(ctx, departmentID, u)
+		return err
+	})
+
+	return ops.rx, ops.err
+}
+
+// DepartmentMerge moves all users and child departments of the `srcID` department
+// into the `dstID` department and deletes the source one. Child departments are not
+// moved if any user has not been moved, and the source department is not deleted
+// if any user or department has not been moved
+func (ya *Ya360) DepartmentMerge(ctx context.Context, srcID, dstID int64, merge DepartmentMergeTx) (DepartmentOpsRx, error) {
+
+	ops := departmentOps{
+		rx: DepartmentOpsRx{
+			DryRun: merge.DryRun,
+		},
+	}
+
+	tree, err := ya.DepartmentsTree(ctx, 1)
+	if err != nil {
+		return ops.rx, err
+	}
+
+	src, err := departmentOpsNode(tree, srcID)
+	if err != nil {
+		return ops.rx, err
+	}
+
+	dst, err := departmentOpsNode(tree, dstID)
+	if err != nil {
+		return ops.rx, err
+	}
+
+	if src.Parent == nil {
+		return ops.rx, fmt.Errorf("root department %d can't be merged", srcID)
+	}
+
+	if departmentOpsInSubtree(src, dst) {
+		return ops.rx, fmt.Errorf("department %d can't be merged into its own subtree", srcID)
+	}
+
+	users, err := ya.UsersAll(ctx, usersCachePerPage, ListOptions{}, 1)
+	if err != nil {
+		return ops.rx, err
+	}
+
+	ya.departmentOpsUsersMove(ctx, &ops, users, []*DepartmentNode{src}, dstID)
+
+	// Keep the source department intact if any user has not been moved
+	if ops.err != nil {
+		return ops.rx, ops.err
+	}
+
+	for _, c := range src.Children {
+		id := c.Department.ID
+		ops.step(DepartmentOpsActionMove, strconv.FormatInt(id, 10), dstID, func() error {
+			_, err := ya.DepartmentUpdate(ctx, id, DepartmentUpdateTx{
+				ParentID: SetInt64(dstID),
+			})
+			return err
+		})
+	}
+
+	if ops.err == nil {
+		ya.departmentOpsDelete(ctx, &ops, src, false)
+	}
+
+	return ops.rx, ops.err
+}
+
+// DepartmentDeleteRecursive deletes department with all its descendants.
+// Users of the deleted departments are moved to the department specified in settings.
+// Departments are deleted starting from the leaves and only if all users have been moved
+func (ya *Ya360) DepartmentDeleteRecursive(ctx context.Context, departmentID int64, del DepartmentDeleteRecursiveTx) (DepartmentOpsRx, error) {
+
+	ops := departmentOps{
+		rx: DepartmentOpsRx{
+			DryRun: del.DryRun,
+		},
+	}
+
+	tree, err := ya.DepartmentsTree(ctx, 1)
+	if err != nil {
+		return ops.rx, err
+	}
+
+	n, err := departmentOpsNode(tree, departmentID)
+	if err != nil {
+		return ops.rx, err
+	}
+
+	if n.Parent == nil {
+		return ops.rx, fmt.Errorf("root department %d can't be deleted", departmentID)
+	}
+
+	usersDst := n.Parent.Department.ID
+	if del.UsersDepartmentID != 0 {
+		usersDst = del.UsersDepartmentID
+	}
+
+	d, err := departmentOpsNode(tree, usersDst)
+	if err != nil {
+		return ops.rx, err
+	}
+
+	if departmentOpsInSubtree(n, d) {
+		return ops.rx, fmt.Errorf("users can't be moved into the deleted department %d", usersDst)
+	}
+
+	users, err := ya.UsersAll(ctx, usersCachePerPage, ListOptions{}, 1)
+	if err != nil {
+		return ops.rx, err
+	}
+
+	ya.departmentOpsUsersMove(ctx, &ops, users, append([]*DepartmentNode{n}, n.Descendants()...), usersDst)
+
+	if ops.err == nil {
+		ya.departmentOpsDelete(ctx, &ops, n, true)
+	}
+
+	return ops.rx, ops.err
+}
+
+// departmentOpsUsersMove moves users of the specified departments into the `dstID` department
+func (ya *Ya360) departmentOpsUsersMove(ctx context.Context, ops *departmentOps, users []UserRx, nodes []*DepartmentNode, dstID int64) {
+
+	ids := make(map[int64]bool)
+	for _, n := range nodes {
+		ids[n.Department.ID] = true
+	}
+
+	for _, u := range users {
+		if ids[u.DepartmentID] == false {
+			continue
+		}
+		userID := u.ID
+		ops.step(DepartmentOpsActionUserMove, userID, dstID, func() error {
+			_, err := ya.UserUpdate(ctx, userID, UserUpdateTx{
+				DepartmentID: SetInt64(dstID),
+			})
+			return err
+		})
+	}
+}
+
+// departmentOpsDelete deletes department. If `recursive` is set, descendants are
+// deleted at first starting from the leaves. Deletion stops on the first error
+func (ya *Ya360) departmentOpsDelete(ctx context.Context, ops *departmentOps, n *DepartmentNode, recursive bool) {
+
+	if recursive == true {
+		for _, c := range n.Children {
+			ya.departmentOpsDelete(ctx, ops, c, true)
+			if ops.err != nil {
+				return
+			}
+		}
+	}
+
+	id := n.Department.ID
+	ops.step(DepartmentOpsActionDelete, strconv.FormatInt(id, 10), 0, func() error {
+		_, err := ya.DepartmentDelete(ctx, id)
+		return err
+	})
+}
+
+// step records the step into the report and executes it unless dry run mode is enabled
+func (o *departmentOps) step(a DepartmentOpsAction, target string, dstID int64, fn func() error) {
+
+	s := DepartmentOpsStep{
+		Action:        a,
+		Target:        target,
+		DestinationID: dstID,
+	}
+
+	if o.rx.DryRun == false {
+		s.Err = fn()
+		if s.Err != nil && o.err == nil {
+			o.err = s.Err
+		}
+	}
+
+	o.rx.Steps = append(o.rx.Steps, s)
+}
+
+func departmentOpsNode(tree *DepartmentTree, departmentID int64) (*DepartmentNode, error) {
+
+	n := tree.Node(departmentID)
+	if n == nil {
+		return nil, fmt.Errorf("department %d: %w", departmentID, ErrNotFound)
+	}
+
+	return n, nil
+}
+
+// departmentOpsInSubtree checks whether `e` is `n` or one of its descendants
+func departmentOpsInSubtree(n, e *DepartmentNode) bool {
+	for ; e != nil; e = e.Parent {
+		if e == n {
+			return true
+		}
+	}
+	return false
+}
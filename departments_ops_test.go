@@ -0,0 +1,196 @@
+package ya360
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+var (
+	testDepartmentDstName  = "TestDepartmentDstName"
+	testDepartmentDstLabel = "testdepartmentdst"
+
+	testDepartmentChildName        = "TestDepartmentChildName"
+	testDepartmentChildLabel       = "testdepartmentchild"
+	testDepartmentChildUpdatedName = "TestDepartmentChildUpdatedName"
+)
+
+func TestDepartmentsOps(t *testing.T) {
+
+	y := testInit(t)
+
+	uCreated := testUserCreate(t, y)
+	defer testUserDetele(t, y, uCreated.ID)
+
+	src := testDepartmentCreate(t, y)
+	dst := testDepartmentOpsCreate(t, y, testDepartmentDstName, testDepartmentDstLabel, 1)
+	child := testDepartmentOpsCreate(t, y, testDepartmentChildName, testDepartmentChildLabel, src.ID)
+
+	if _, err := y.UserUpdate(context.Background(), uCreated.ID, UserUpdateTx{
+		DepartmentID: SetInt64(child.ID),
+	}); err != nil {
+		t.Fatal("User update error:", err)
+	}
+
+	testDepartmentMove(t, y, child.ID, dst.ID)
+	testDepartmentMerge(t, y, dst.ID, src.ID)
+	testDepartmentDeleteRecursive(t, y, src.ID, child.ID, uCreated.ID)
+}
+
+func TestDepartmentMergeUserMoveFailed(t *testing.T) {
+
+	var departmentUpdates int32
+
+	y := testAPIInit(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/departments"):
+			w.Write([]byte(`{"departments":[{"id":1},{"id":2,"parentId":1},{"id":3,"parentId":2},{"id":4,"parentId":1}],"page":1,"pages":1}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users"):
+			w.Write([]byte(`{"users":[{"id":"10","departmentId":2}],"page":1,"pages":1}`))
+		case r.Method == http.MethodPatch && strings.Contains(r.URL.Path, "/departments/"):
+			atomic.AddInt32(&departmentUpdates, 1)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":3,"message":"invalid"}`))
+		}
+	}, Settings{})
+
+	r, err := y.DepartmentMerge(context.Background(), 2, 4, DepartmentMergeTx{})
+	if err == nil {
+		t.Fatal("Department merge error: user move error expected")
+	}
+
+	if len(r.Steps) != 1 || r.Steps[0].Action != DepartmentOpsActionUserMove || atomic.LoadInt32(&departmentUpdates) != 0 {
+		t.Fatalf("Department merge error: child departments must not be moved (steps: %v)", r.Steps)
+	}
+
+	t.Logf("Department merge user move failed: success")
+}
+
+func testDepartmentOpsCreate(t *testing.T, y Ya360, name, label string, parentID int64) DepartmentRx {
+
+	d, err := y.DepartmentCreate(context.Background(), DepartmentCreateTx{
+		Name:     name,
+		ParentID: parentID,
+		Label:    label,
+	})
+	if err != nil {
+		t.Fatal("Department create error:", err)
+	}
+
+	return d
+}
+
+func testDepartmentMove(t *testing.T, y Ya360, departmentID, parentID int64) {
+
+	r, err := y.DepartmentMove(context.Background(), departmentID, DepartmentMoveTx{
+		ParentID: parentID,
+		Name:     testDepartmentChildUpdatedName,
+		DryRun:   true,
+	})
+	if err != nil {
+		t.Fatal("Department move error:", err)
+	}
+
+	d, err := y.DepartmentGet(context.Background(), departmentID)
+	if err != nil {
+		t.Fatal("Department move error:", err)
+	}
+
+	if len(r.Steps) != 1 || d.ParentID == parentID {
+		t.Fatalf("Department move error: dry run must not change department (steps: %v)", r.Steps)
+	}
+
+	if _, err := y.DepartmentMove(context.Background(), parentID, DepartmentMoveTx{
+		ParentID: parentID,
+	}); err == nil {
+		t.Fatal("Department move error: move into itself must fail")
+	}
+
+	if _, err := y.DepartmentMove(context.Background(), departmentID, DepartmentMoveTx{
+		ParentID: parentID,
+		Name:     testDepartmentChildUpdatedName,
+	}); err != nil {
+		t.Fatal("Department move error:", err)
+	}
+
+	d, err = y.DepartmentGet(context.Background(), departmentID)
+	if err != nil {
+		t.Fatal("Department move error:", err)
+	}
+
+	if d.ParentID != parentID || d.Name != testDepartmentChildUpdatedName {
+		t.Fatalf("Department move error: incorrect parent or name (returned: %d, %s)", d.ParentID, d.Name)
+	}
+
+	t.Logf("Department move: success")
+}
+
+func testDepartmentMerge(t *testing.T, y Ya360, srcID, dstID int64) {
+
+	r, err := y.DepartmentMerge(context.Background(), srcID, dstID, DepartmentMergeTx{
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatal("Department merge error:", err)
+	}
+
+	// Child department move and source department delete
+	if len(r.Steps) != 2 {
+		t.Fatalf("Department merge error: incorrect dry run steps (returned: %v)", r.Steps)
+	}
+
+	if _, err := y.DepartmentGet(context.Background(), srcID); err != nil {
+		t.Fatal("Department merge error: dry run must not delete department:", err)
+	}
+
+	if _, err := y.DepartmentMerge(context.Background(), srcID, dstID, DepartmentMergeTx{}); err != nil {
+		t.Fatal("Department merge error:", err)
+	}
+
+	if _, err := y.DepartmentGet(context.Background(), srcID); IsNotFound(err) == false {
+		t.Fatal("Department merge error: source department must be deleted, got:", err)
+	}
+
+	t.Logf("Department merge: success")
+}
+
+func testDepartmentDeleteRecursive(t *testing.T, y Ya360, departmentID, childID int64, userID string) {
+
+	r, err := y.DepartmentDeleteRecursive(context.Background(), departmentID, DepartmentDeleteRecursiveTx{
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatal("Department delete recursive error:", err)
+	}
+
+	// User move, child department delete and department delete
+	if len(r.Steps) != 3 || r.Steps[1].Target != strconv.FormatInt(childID, 10) {
+		t.Fatalf("Department delete recursive error: incorrect dry run steps (returned: %v)", r.Steps)
+	}
+
+	if _, err := y.DepartmentDeleteRecursive(context.Background(), departmentID, DepartmentDeleteRecursiveTx{}); err != nil {
+		t.Fatal("Department delete recursive error:", err)
+	}
+
+	for _, id := range []int64{childID, departmentID} {
+		if _, err := y.DepartmentGet(context.Background(), id); IsNotFound(err) == false {
+			t.Fatal("Department delete recursive error: department must be deleted, got:", err)
+		}
+	}
+
+	u, err := y.UserGet(context.Background(), userID)
+	if err != nil {
+		t.Fatal("Department delete recursive error:", err)
+	}
+
+	if u.DepartmentID != 1 {
+		t.Fatalf("Department delete recursive error: incorrect user department (returned: %d)", u.DepartmentID)
+	}
+
+	t.Logf("Department delete recursive: success")
+}
@@ -1,14 +1,14 @@
 package ya360
 
 import (
-	"os"
-	"strconv"
+	"context"
 	"testing"
 )
 
 var (
 	testDepartmentName        = "TestDepartmentName"
 	testDepartmentUpdatedName = "TestDepartmentUpdatedName"
+	testDepartmentDescription = "Test department description"
 
 	testDepartmentLabel = "testdepartmentemail"
 	testDepartmentAlias = "testdepartmentalias"
@@ -16,25 +16,14 @@ var (
 
 func TestDepartmentsCRUD(t *testing.T) {
 
-	oAuth := os.Getenv("YA360_OAUTH")
-	orgID, err := strconv.ParseInt(os.Getenv("YA360_ORG_ID"), 10, 64)
-	if err != nil {
-		t.Fatal("Init error: make sure environment variable `YA360_ORG_ID` correctly defined:", err)
-	}
-	if len(oAuth) == 0 {
-		t.Fatal("Init error: make sure environment variable `YA360_OAUTH` correctly defined")
-	}
-
-	y := Init(Settings{
-		OAuth: oAuth,
-		OrgID: orgID,
-	})
+	y := testInit(t)
 
 	dCreated := testDepartmentCreate(t, y)
 	defer testDepartmentDetele(t, y, dCreated.ID)
 
 	testDepartmentGet(t, y, dCreated.ID)
 	testDepartmentsList(t, y)
+	testDepartmentsListOptions(t, y, dCreated.ID)
 
 	testDepartmentUpdate(t, y, dCreated.ID)
 
@@ -45,7 +34,7 @@ func TestDepartmentsCRUD(t *testing.T) {
 
 func testDepartmentCreate(t *testing.T, y Ya360) DepartmentRx {
 
-	d, err := y.DepartmentCreate(DepartmentCreateTx{
+	d, err := y.DepartmentCreate(context.Background(), DepartmentCreateTx{
 		Name:     testDepartmentName,
 		ParentID: 1,
 		Label:    testDepartmentLabel,
@@ -65,7 +54,7 @@ func testDepartmentCreate(t *testing.T, y Ya360) DepartmentRx {
 
 func testDepartmentGet(t *testing.T, y Ya360, departmentID int64) {
 
-	d, err := y.DepartmentGet(departmentID)
+	d, err := y.DepartmentGet(context.Background(), departmentID)
 	if err != nil {
 		t.Fatal("Department get error:", err)
 	}
@@ -83,7 +72,7 @@ func testDepartmentGet(t *testing.T, y Ya360, departmentID int64) {
 
 func testDepartmentsList(t *testing.T, y Ya360) {
 
-	d, err := y.DepartmentsList(1, 1000, 0, OrderByID)
+	d, err := y.DepartmentsList(context.Background(), 1, 1000, ListOptions{OrderBy: OrderByID})
 	if err != nil {
 		t.Fatal("Departments list error:", err)
 	}
@@ -98,17 +87,62 @@ func testDepartmentsList(t *testing.T, y Ya360) {
 	t.Fatal("Departments list error: created department not found")
 }
 
+func testDepartmentsListOptions(t *testing.T, y Ya360, departmentID int64) {
+
+	parentID := int64(1)
+
+	d, err := y.DepartmentsAll(context.Background(), 1000, ListOptions{
+		ParentID:  &parentID,
+		OrderBy:   OrderByName,
+		Direction: DirectionDesc,
+	}, 1)
+	if err != nil {
+		t.Fatal("Departments list options error:", err)
+	}
+
+	found := false
+	for i, e := range d {
+		if e.ParentID != 1 {
+			t.Fatalf("Departments list options error: incorrect parent (returned: %d)", e.ParentID)
+		}
+		if i > 0 && d[i-1].Name < e.Name {
+			t.Fatal("Departments list options error: incorrect order")
+		}
+		if e.ID == departmentID {
+			found = true
+		}
+	}
+
+	if found == false {
+		t.Fatal("Departments list options error: created department not found")
+	}
+
+	t.Logf("Departments list options: success")
+}
+
 func testDepartmentUpdate(t *testing.T, y Ya360, departmentID int64) {
 
-	d, err := y.DepartmentUpdate(departmentID, DepartmentUpdateTx{
-		Name: testDepartmentUpdatedName,
+	d, err := y.DepartmentUpdate(context.Background(), departmentID, DepartmentUpdateTx{
+		Name:        SetString(testDepartmentUpdatedName),
+		Description: SetString(testDepartmentDescription),
+	})
+	if err != nil {
+		t.Fatal("Department update error:", err)
+	}
+
+	if d.Name != testDepartmentUpdatedName || d.Description != testDepartmentDescription {
+		t.Fatalf("Department update error: incorrect new name or description (returned: %s, %s)", d.Name, d.Description)
+	}
+
+	d, err = y.DepartmentUpdate(context.Background(), departmentID, DepartmentUpdateTx{
+		Description: ClearString(),
 	})
 	if err != nil {
 		t.Fatal("Department update error:", err)
 	}
 
-	if d.Name != testDepartmentUpdatedName {
-		t.Fatalf("Department update error: incorrect new name (returned: %s)", d.Name)
+	if d.Name != testDepartmentUpdatedName || len(d.Description) > 0 {
+		t.Fatalf("Department update error: description must be cleared and name kept (returned: %s, %s)", d.Name, d.Description)
 	}
 
 	t.Logf("Department update: success")
@@ -116,7 +150,7 @@ func testDepartmentUpdate(t *testing.T, y Ya360, departmentID int64) {
 
 func testDepartmentAliasAdd(t *testing.T, y Ya360, departmentID int64) {
 
-	d, err := y.DepartmentAliasAdd(departmentID, DepartmentAliasAddTx{
+	d, err := y.DepartmentAliasAdd(context.Background(), departmentID, DepartmentAliasAddTx{
 		Alias: testDepartmentAlias,
 	})
 	if err != nil {
@@ -135,7 +169,7 @@ func testDepartmentAliasAdd(t *testing.T, y Ya360, departmentID int64) {
 
 func testDepartmentAliasDelete(t *testing.T, y Ya360, departmentID int64) {
 
-	d, err := y.DepartmentAliasDelete(departmentID, testDepartmentAlias)
+	d, err := y.DepartmentAliasDelete(context.Background(), departmentID, testDepartmentAlias)
 	if err != nil {
 		t.Fatal("Department delete alias error:", err)
 	}
@@ -149,7 +183,7 @@ func testDepartmentAliasDelete(t *testing.T, y Ya360, departmentID int64) {
 
 func testDepartmentDetele(t *testing.T, y Ya360, departmentID int64) {
 
-	d, err := y.DepartmentDelete(departmentID)
+	d, err := y.DepartmentDelete(context.Background(), departmentID)
 	if err != nil {
 		t.Fatal("Department delete error:", err)
 	}
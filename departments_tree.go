@@ -0,0 +1,274 @@
+package ya360
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DepartmentTree contains departments hierarchy
+type DepartmentTree struct {
+
+	// Roots contains top level departments, i.e. departments
+	// without parent or with parent missing in the tree
+	Roots []*DepartmentNode
+
+	nodes map[int64]*DepartmentNode
+}
+
+// DepartmentNode contains department within the tree
+type DepartmentNode struct {
+	Department DepartmentRx
+	Parent     *DepartmentNode
+	Children   []*DepartmentNode
+}
+
+// DepartmentCycleError is returned if departments
+// hierarchy contains a cycle over parent IDs
+type DepartmentCycleError struct {
+	IDs []int64
+}
+
+func (e DepartmentCycleError) Error() string {
+	return fmt.Sprintf("departments cycle detected: %v", e.IDs)
+}
+
+// departmentsTreePerPage is a page size used to get departments to build the tree
+const departmentsTreePerPage = 1000
+
+// DepartmentsTree gets all departments of the organization and builds the tree.
+// If `workers` is greater than 1 departments pages are fetched concurrently
+func (ya *Ya360) DepartmentsTree(ctx context.Context, workers int) (*DepartmentTree, error) {
+
+	deps, err := ya.DepartmentsAll(ctx, departmentsTreePerPage, ListOptions{}, workers)
+	if err != nil {
+		return nil, err
+	}
+
+	return DepartmentTreeInit(deps)
+}
+
+// DepartmentTreeInit builds the tree from specified departments.
+// Children of every node are sorted by department ID
+func DepartmentTreeInit(departments []DepartmentRx) (*DepartmentTree, error) {
+
+	t := &DepartmentTree{
+		nodes: make(map[int64]*DepartmentNode),
+	}
+
+	for _, d := range departments {
+		if _, ok := t.nodes[d.ID]; ok == true {
+			return nil, fmt.Errorf("duplicate department ID: %d", d.ID)
+		}
+		t.nodes[d.ID] = &DepartmentNode{
+			Department: d,
+		}
+	}
+
+	for _, id := range t.ids() {
+
+		n := t.nodes[id]
+
+		p, ok := t.nodes[n.Department.ParentID]
+		if ok == false || n.Department.ParentID == id {
+			if ok == true {
+				return nil, DepartmentCycleError{IDs: []int64{id}}
+			}
+			t.Roots = append(t.Roots, n)
+			continue
+		}
+
+		n.Parent = p
+		p.Children = append(p.Children, n)
+	}
+
+	if err := t.cycleCheck(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Node returns node of the department with specified ID or nil if not found
+func (t *DepartmentTree) Node(departmentID int64) *DepartmentNode {
+	return t.nodes[departmentID]
+}
+
+// Len returns number of departments within the tree
+func (t *DepartmentTree) Len() int {
+	return len(t.nodes)
+}
+
+// Walk calls `fn` for every node in the depth-first order starting from the roots.
+// `depth` is zero for the roots. Walking stops on the first error returned by `fn`
+func (t *DepartmentTree) Walk(fn func(n *DepartmentNode, depth int) error) error {
+	for _, r := range t.Roots {
+		if err := r.Walk(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Text renders the tree as indented text with a department per line
+func (t *DepartmentTree) Text() string {
+
+	var b strings.Builder
+
+	t.Walk(func(n *DepartmentNode, depth int) error {
+		fmt.Fprintf(&b, "%s%s (id: %d, members: %d)\n", strings.Repeat("  ", depth), n.Department.Name, n.Department.ID, n.MembersCount())
+		return nil
+	})
+
+	return b.String()
+}
+
+// DOT renders the tree as a graph in the Graphviz DOT language
+func (t *DepartmentTree) DOT() string {
+
+	var b strings.Builder
+
+	b.WriteString("digraph departments {\n")
+
+	t.Walk(func(n *DepartmentNode, depth int) error {
+		fmt.Fprintf(&b, "\t%d [label=\"%s\"];\n", n.Department.ID, dotEscape(n.Department.Name))
+		if n.Parent != nil {
+			fmt.Fprintf(&b, "\t%d -> %d;\n", n.Parent.Department.ID, n.Department.ID)
+		}
+		return nil
+	})
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// Walk calls `fn` for the node and all its descendants in the depth-first order.
+// `depth` is counted from the node the walking starts with
+func (n *DepartmentNode) Walk(fn func(n *DepartmentNode, depth int) error) error {
+	return n.walk(0, fn)
+}
+
+// Path returns nodes from the root to the node inclusive
+func (n *DepartmentNode) Path() []*DepartmentNode {
+
+	p := []*DepartmentNode{}
+
+	for e := n; e != nil; e = e.Parent {
+		p = append([]*DepartmentNode{e}, p...)
+	}
+
+	return p
+}
+
+// Depth returns number of the node ancestors
+func (n *DepartmentNode) Depth() int {
+	return len(n.Path()) - 1
+}
+
+// Descendants returns all nodes of the subtree except the node itself
+func (n *DepartmentNode) Descendants() []*DepartmentNode {
+
+	d := []*DepartmentNode{}
+
+	n.Walk(func(e *DepartmentNode, depth int) error {
+		if e != n {
+			d = append(d, e)
+		}
+		return nil
+	})
+
+	return d
+}
+
+// MembersCount returns number of members of the department and all its
+// descendants. Yandex 360 already counts members of nested departments
+// in `DepartmentRx.MembersCount`, so it is returned as is
+func (n *DepartmentNode) MembersCount() int64 {
+	return n.Department.MembersCount
+}
+
+// DirectMembersCount returns number of members of the department
+// itself, not including members of the nested departments
+func (n *DepartmentNode) DirectMembersCount() int64 {
+
+	c := n.Department.MembersCount
+
+	for _, e := range n.Children {
+		c -= e.Department.MembersCount
+	}
+
+	return c
+}
+
+func (n *DepartmentNode) walk(depth int, fn func(n *DepartmentNode, depth int) error) error {
+
+	if err := fn(n, depth); err != nil {
+		return err
+	}
+
+	for _, c := range n.Children {
+		if err := c.walk(depth+1, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ids returns sorted IDs of all nodes
+func (t *DepartmentTree) ids() []int64 {
+
+	ids := []int64{}
+
+	for id := range t.nodes {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+// cycleCheck checks that all nodes are reachable from the roots.
+// Unreachable nodes are the ones within the parents cycle
+func (t *DepartmentTree) cycleCheck() error {
+
+	reached := make(map[int64]bool)
+
+	t.Walk(func(n *DepartmentNode, depth int) error {
+		reached[n.Department.ID] = true
+		return nil
+	})
+
+	if len(reached) == len(t.nodes) {
+		return nil
+	}
+
+	// Find the cycle going up from the first unreachable node
+	for _, id := range t.ids() {
+
+		if reached[id] == true {
+			continue
+		}
+
+		seen := make(map[int64]int)
+		path := []int64{}
+
+		for n := t.nodes[id]; ; n = n.Parent {
+			if i, ok := seen[n.Department.ID]; ok == true {
+				return DepartmentCycleError{IDs: path[i:]}
+			}
+			seen[n.Department.ID] = len(path)
+			path = append(path, n.Department.ID)
+		}
+	}
+
+	return nil
+}
+
+// dotEscape escapes string to be used as a quoted DOT identifier
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,122 @@
+package ya360
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDepartmentsTree(t *testing.T) {
+
+	y := testInit(t)
+
+	dCreated := testDepartmentCreate(t, y)
+	defer testDepartmentDetele(t, y, dCreated.ID)
+
+	uCreated := testUserCreate(t, y)
+	defer testUserDetele(t, y, uCreated.ID)
+
+	if _, err := y.UserUpdate(context.Background(), uCreated.ID, UserUpdateTx{
+		DepartmentID: SetInt64(dCreated.ID),
+	}); err != nil {
+		t.Fatal("User update error:", err)
+	}
+	defer y.UserUpdate(context.Background(), uCreated.ID, UserUpdateTx{
+		DepartmentID: SetInt64(1),
+	})
+
+	tree, err := y.DepartmentsTree(context.Background(), 1)
+	if err != nil {
+		t.Fatal("Departments tree error:", err)
+	}
+
+	n := tree.Node(dCreated.ID)
+	if n == nil {
+		t.Fatal("Departments tree error: created department not found")
+	}
+
+	p := n.Path()
+	if len(p) != 2 || p[0].Department.ID != 1 || p[1] != n {
+		t.Fatalf("Departments tree error: incorrect path to root (returned: %v)", p)
+	}
+
+	if n.MembersCount() != 1 || p[0].MembersCount() < 1 {
+		t.Fatalf("Departments tree error: incorrect members count (returned: %d)", n.MembersCount())
+	}
+
+	if strings.Contains(tree.Text(), "  "+testDepartmentName+" (") == false {
+		t.Fatalf("Departments tree error: created department not rendered:\n%s", tree.Text())
+	}
+
+	t.Logf("Departments tree: success")
+}
+
+func TestDepartmentTreeInit(t *testing.T) {
+
+	tree, err := DepartmentTreeInit([]DepartmentRx{
+		{ID: 1, Name: "All", MembersCount: 6},
+		{ID: 3, Name: "Backend", ParentID: 2, MembersCount: 2},
+		{ID: 2, Name: "Development", ParentID: 1, MembersCount: 5},
+		{ID: 4, Name: "Sales \"B2B\"", ParentID: 1},
+	})
+	if err != nil {
+		t.Fatal("Department tree init error:", err)
+	}
+
+	if len(tree.Roots) != 1 || tree.Len() != 4 {
+		t.Fatal("Department tree init error: incorrect roots or nodes count")
+	}
+
+	if c := tree.Roots[0].MembersCount(); c != 6 {
+		t.Fatalf("Department tree init error: incorrect members count (returned: %d)", c)
+	}
+
+	if c := tree.Node(2).DirectMembersCount(); c != 3 {
+		t.Fatalf("Department tree init error: incorrect direct members count (returned: %d)", c)
+	}
+
+	if d := tree.Node(3).Depth(); d != 2 {
+		t.Fatalf("Department tree init error: incorrect depth (returned: %d)", d)
+	}
+
+	if d := tree.Node(2).Descendants(); len(d) != 1 || d[0].Department.ID != 3 {
+		t.Fatalf("Department tree init error: incorrect descendants (returned: %v)", d)
+	}
+
+	text := "All (id: 1, members: 6)\n" +
+		"  Development (id: 2, members: 5)\n" +
+		"    Backend (id: 3, members: 2)\n" +
+		"  Sales \"B2B\" (id: 4, members: 0)\n"
+	if tree.Text() != text {
+		t.Fatalf("Department tree init error: incorrect text:\n%s", tree.Text())
+	}
+
+	dot := "digraph departments {\n" +
+		"\t1 [label=\"All\"];\n" +
+		"\t2 [label=\"Development\"];\n" +
+		"\t1 -> 2;\n" +
+		"\t3 [label=\"Backend\"];\n" +
+		"\t2 -> 3;\n" +
+		"\t4 [label=\"Sales \\\"B2B\\\"\"];\n" +
+		"\t1 -> 4;\n" +
+		"}\n"
+	if tree.DOT() != dot {
+		t.Fatalf("Department tree init error: incorrect DOT:\n%s", tree.DOT())
+	}
+
+	_, err = DepartmentTreeInit([]DepartmentRx{
+		{ID: 1, Name: "All"},
+		{ID: 2, ParentID: 4},
+		{ID: 3, ParentID: 2},
+		{ID: 4, ParentID: 3},
+		{ID: 5, ParentID: 4},
+	})
+
+	var e DepartmentCycleError
+	if errors.As(err, &e) == false || len(e.IDs) != 3 {
+		t.Fatal("Department tree init error: cycle error expected, got:", err)
+	}
+
+	t.Logf("Department tree init: success")
+}
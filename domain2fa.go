@@ -0,0 +1,104 @@
+package ya360
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Domain2FARx contains organization two-factor authentication settings
+type Domain2FARx struct {
+	Enabled   bool           `json:"enabled"`
+	EnabledAt string         `json:"enabledAt"`
+	Duration  int64          `json:"duration"`
+	Scope     Domain2FAScope `json:"scope"`
+}
+
+// Domain2FAScope contains users two-factor authentication is mandatory for.
+// Either all users or only members of the specified groups
+type Domain2FAScope struct {
+	AllUsers bool    `json:"allUsers"`
+	GroupIDs []int64 `json:"groupIds,omitempty"`
+}
+
+// Domain2FAEnableTx contains data to enable mandatory two-factor authentication
+type Domain2FAEnableTx struct {
+
+	// Duration is a grace period (in seconds) during which
+	// users are able to log in without configured 2FA
+	Duration int64 `json:"duration"`
+
+	// LogoutUsers logs out users without configured 2FA
+	// after the grace period
+	LogoutUsers bool `json:"logoutUsers"`
+
+	Scope Domain2FAScope `json:"scope"`
+}
+
+// Domain2FAGet gets organization two-factor authentication settings
+// Link: https://yandex.ru/dev/api360/doc/ref/DomainSecurity2faService/DomainSecurity2faService_Get.html
+func (ya *Ya360) Domain2FAGet(ctx context.Context) (Domain2FARx, error) {
+
+	var (
+		resp Domain2FARx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/security/v1/org/%d/domain_2fa", ya.s.OrgID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.get(ctx, ur, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// Domain2FAEnable enables mandatory two-factor authentication for
+// the whole organization or selected groups in accordance with `settings`
+// Link: https://yandex.ru/dev/api360/doc/ref/DomainSecurity2faService/DomainSecurity2faService_Enable.html
+func (ya *Ya360) Domain2FAEnable(ctx context.Context, settings Domain2FAEnableTx) (Domain2FARx, error) {
+
+	var (
+		resp Domain2FARx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/security/v1/org/%d/domain_2fa", ya.s.OrgID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.alter(ctx, http.MethodPost, ur, settings, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// Domain2FADisable disables mandatory two-factor authentication for organization
+// Link: https://yandex.ru/dev/api360/doc/ref/DomainSecurity2faService/DomainSecurity2faService_Disable.html
+func (ya *Ya360) Domain2FADisable(ctx context.Context) (Domain2FARx, error) {
+
+	var (
+		resp Domain2FARx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/security/v1/org/%d/domain_2fa", ya.s.OrgID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.alter(ctx, http.MethodDelete, ur, nil, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
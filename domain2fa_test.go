@@ -0,0 +1,88 @@
+package ya360
+
+import (
+	"context"
+	"testing"
+)
+
+var (
+	testDomain2FADuration int64 = 86400
+)
+
+func TestDomain2FA(t *testing.T) {
+
+	y := testInit(t)
+
+	d := testDomain2FAGet(t, y)
+	defer testDomain2FARestore(t, y, d)
+
+	testDomain2FAEnable(t, y)
+	testDomain2FADisable(t, y)
+}
+
+func testDomain2FAGet(t *testing.T, y Ya360) Domain2FARx {
+
+	d, err := y.Domain2FAGet(context.Background())
+	if err != nil {
+		t.Fatal("Domain 2FA get error:", err)
+	}
+
+	t.Logf("Domain 2FA get: success")
+
+	return d
+}
+
+func testDomain2FAEnable(t *testing.T, y Ya360) {
+
+	d, err := y.Domain2FAEnable(context.Background(), Domain2FAEnableTx{
+		Duration: testDomain2FADuration,
+		Scope: Domain2FAScope{
+			AllUsers: true,
+		},
+	})
+	if err != nil {
+		t.Fatal("Domain 2FA enable error:", err)
+	}
+
+	if d.Enabled == false || d.Duration != testDomain2FADuration || d.Scope.AllUsers == false {
+		t.Fatalf("Domain 2FA enable error: incorrect settings (returned: %+v)", d)
+	}
+
+	t.Logf("Domain 2FA enable: success")
+}
+
+func testDomain2FADisable(t *testing.T, y Ya360) {
+
+	if _, err := y.Domain2FADisable(context.Background()); err != nil {
+		t.Fatal("Domain 2FA disable error:", err)
+	}
+
+	d, err := y.Domain2FAGet(context.Background())
+	if err != nil {
+		t.Fatal("Domain 2FA disable error:", err)
+	}
+
+	if d.Enabled {
+		t.Fatal("Domain 2FA disable error: 2FA is still enabled")
+	}
+
+	t.Logf("Domain 2FA disable: success")
+}
+
+// testDomain2FARestore restores organization settings changed by tests
+func testDomain2FARestore(t *testing.T, y Ya360, d Domain2FARx) {
+
+	if d.Enabled == false {
+		return
+	}
+
+	_, err := y.Domain2FAEnable(context.Background(), Domain2FAEnableTx{
+		Duration: d.Duration,
+		Scope:    d.Scope,
+	})
+	if err != nil {
+		t.Fatal("Domain 2FA restore error:", err)
+	}
+
+	t.Logf("Domain 2FA restore: success")
+}
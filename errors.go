@@ -0,0 +1,169 @@
+package ya360
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error contains error occurred during request to Yandex 360
+type Error struct {
+
+	// Code is an HTTP status code of response.
+	// Zero value means request has not been completed
+	Code int
+
+	// Text is an error description
+	Text string
+
+	// APICode is an error code returned by Yandex 360
+	APICode int
+
+	// Message is an error message returned by Yandex 360
+	Message string
+
+	// Details contains error details returned by Yandex 360
+	Details []ErrorDetail
+
+	// RequestID is an ID of the failed request
+	RequestID string
+
+	// Body contains raw response body (truncated up to `errorBodyMaxLen` bytes)
+	Body string
+
+	// Attempts is a number of attempts made to complete the request
+	Attempts int
+
+	err error
+}
+
+// ErrorDetail contains error details entry
+type ErrorDetail struct {
+	Type   string
+	Fields map[string]interface{}
+}
+
+type errorRx struct {
+	Code    int           `json:"code"`
+	Details []ErrorDetail `json:"details"`
+	Message string        `json:"message"`
+}
+
+// errorBodyMaxLen is a max length of response body kept in `Error`
+const errorBodyMaxLen = 1024
+
+// Yandex 360 API error codes
+const (
+	APICodeNotFound          = 5
+	APICodeAlreadyExists     = 6
+	APICodePermissionDenied  = 7
+	APICodeResourceExhausted = 8
+	APICodeUnauthenticated   = 16
+)
+
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+func (e Error) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("%d, %s (attempts: %d)", e.Code, e.Text, e.Attempts)
+	}
+	return fmt.Sprintf("%d, %s", e.Code, e.Text)
+}
+
+// Unwrap returns the underlying error (e.g. network or context error) if any
+func (e Error) Unwrap() error {
+	return e.err
+}
+
+// Is allows to check error kind with `errors.Is()` and sentinel errors
+func (e Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Code == http.StatusNotFound || e.APICode == APICodeNotFound
+	case ErrConflict:
+		return e.Code == http.StatusConflict || e.APICode == APICodeAlreadyExists
+	case ErrRateLimited:
+		return e.Code == http.StatusTooManyRequests || e.APICode == APICodeResourceExhausted
+	case ErrUnauthorized:
+		return e.Code == http.StatusUnauthorized || e.APICode == APICodeUnauthenticated
+	}
+	return false
+}
+
+// UserNotFoundError is returned by user lookup functions
+// if there is no user matching the specified field value
+type UserNotFoundError struct {
+	Field string
+	Value string
+}
+
+func (e UserNotFoundError) Error() string {
+	return fmt.Sprintf("user not found: %s = %s", e.Field, e.Value)
+}
+
+// Is allows to check the error with `errors.Is(err, ErrNotFound)`
+func (e UserNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// IsNotFound checks the error is caused by a missing object
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict checks the error is caused by a conflict with an existing object
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsRateLimited checks the error is caused by requests throttling
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsUnauthorized checks the error is caused by a wrong or expired token
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// UnmarshalJSON decodes error details entry
+func (d *ErrorDetail) UnmarshalJSON(data []byte) error {
+
+	f := make(map[string]interface{})
+
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+
+	if t, ok := f["@type"].(string); ok {
+		d.Type = t
+	}
+	delete(f, "@type")
+
+	d.Fields = f
+
+	return nil
+}
+
+// errorWrap converts error occurred during request into `Error`
+func errorWrap(status, attempts int, err error) error {
+
+	e, ok := err.(Error)
+	if ok == false {
+		e = Error{
+			Code: status,
+			Text: err.Error(),
+			err:  err,
+		}
+	}
+
+	e.Attempts = attempts
+
+	return e
+}
@@ -1,10 +1,12 @@
 package ya360
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 )
 
 // GroupsRx contains groups list
@@ -42,11 +44,40 @@ type GroupMemberAddRx struct {
 	Type  MemberType `json:"type"`
 }
 
+// GroupMemberDeleteRx contains result of group member delete operation
+type GroupMemberDeleteRx struct {
+	Deleted bool       `json:"deleted"`
+	ID      string     `json:"id"`
+	Type    MemberType `json:"type"`
+}
+
+// GroupMembersDeleteAllRx contains members deleted from group
+type GroupMembersDeleteAllRx struct {
+	Departments []GroupMembersDeleteAllDepartmentRx `json:"departments"`
+	Groups      []GroupMembersDeleteAllGroupRx      `json:"groups"`
+	Users       []GroupMembersDeleteAllUserRx       `json:"users"`
+}
+
+// GroupMembersDeleteAllDepartmentRx contains deleted group member for type `department`
+type GroupMembersDeleteAllDepartmentRx struct {
+	ID int64 `json:"id"`
+}
+
+// GroupMembersDeleteAllGroupRx contains deleted group member for type `group`
+type GroupMembersDeleteAllGroupRx struct {
+	ID int64 `json:"id"`
+}
+
+// GroupMembersDeleteAllUserRx contains deleted group member for type `user`
+type GroupMembersDeleteAllUserRx struct {
+	ID string `json:"id"`
+}
+
 // GroupMembersListRx contains group member lists
 type GroupMembersListRx struct {
-	Departments GroupMemberDepartmentRx `json:"departments"`
-	Groups      GroupMemberGroupRx      `json:"groups"`
-	Users       GroupMemberUserRx       `json:"users"`
+	Departments []GroupMemberDepartmentRx `json:"departments"`
+	Groups      []GroupMemberGroupRx      `json:"groups"`
+	Users       []GroupMemberUserRx       `json:"users"`
 }
 
 // GroupMemberDepartmentRx contains group member for type `department`
@@ -63,10 +94,10 @@ type GroupMemberGroupRx struct {
 	Name         string `json:"name"`
 }
 
-// GroupMemberGroupRx contains group member for type `user`
+// GroupMemberUserRx contains group member for type `user`
 type GroupMemberUserRx struct {
-	AvatarId     string   `json:"avatarId"`
-	DepartmentId int64    `json:"departmentId"`
+	AvatarID     string   `json:"avatarId"`
+	DepartmentID int64    `json:"departmentId"`
 	Email        string   `json:"email"`
 	Gender       string   `json:"gender"`
 	ID           string   `json:"id"`
@@ -75,12 +106,54 @@ type GroupMemberUserRx struct {
 	Position     string   `json:"position"`
 }
 
+// GroupAdminsRx contains group admins list
+type GroupAdminsRx struct {
+	AdminIDs []string `json:"adminIds"`
+}
+
+// GroupAdminsUpdateRx contains result of group admins update operation
+type GroupAdminsUpdateRx struct {
+	AdminIDs []string `json:"adminIds"`
+	GroupID  int64    `json:"groupId"`
+}
+
+// GroupAdminsDeleteRx contains result of group admins delete operation
+type GroupAdminsDeleteRx struct {
+	Deleted bool  `json:"deleted"`
+	GroupID int64 `json:"groupId"`
+}
+
 // GroupDeleteRx contains result of group delete operation
 type GroupDeleteRx struct {
 	ID      int64 `json:"id"`
 	Removed bool  `json:"removed"`
 }
 
+// GroupSetMembersReport contains report of group members replacement
+type GroupSetMembersReport struct {
+	Added   []MemberIDType
+	Deleted []MemberIDType
+	Failed  []GroupSetMembersFailure
+}
+
+// GroupSetMembersFailure contains failed operation of group members replacement
+type GroupSetMembersFailure struct {
+	Member MemberIDType
+	Op     GroupSetMembersOp
+	Err    error
+}
+
+type GroupSetMembersOp string
+
+const (
+	GroupSetMembersOpAdd    GroupSetMembersOp = "add"
+	GroupSetMembersOpDelete GroupSetMembersOp = "delete"
+)
+
+func (o GroupSetMembersOp) String() string {
+	return string(o)
+}
+
 // GroupCreateTx contains data to create new group
 type GroupCreateTx struct {
 	AdminIDs    []string       `json:"adminIds,omitempty"`
@@ -91,14 +164,20 @@ type GroupCreateTx struct {
 	Name        string         `json:"name"`
 }
 
-// GroupUpdateTx contains data to update group
+// GroupUpdateTx contains data to update group.
+// Nil fields are not changed, see `OptString` for details
 type GroupUpdateTx struct {
-	AdminIDs    []string       `json:"adminIds,omitempty"`
-	Description string         `json:"description,omitempty"`
-	ExternalID  string         `json:"externalId,omitempty"`
-	Label       string         `json:"label,omitempty"`
-	Members     []MemberIDType `json:"members,omitempty"`
-	Name        string         `json:"name,omitempty"`
+	AdminIDs    *[]string       `json:"adminIds,omitempty"`
+	Description *OptString      `json:"description,omitempty"`
+	ExternalID  *OptString      `json:"externalId,omitempty"`
+	Label       *OptString      `json:"label,omitempty"`
+	Members     *[]MemberIDType `json:"members,omitempty"`
+	Name        *OptString      `json:"name,omitempty"`
+}
+
+// GroupAdminsUpdateTx contains data to replace group admins
+type GroupAdminsUpdateTx struct {
+	AdminIDs []string `json:"adminIds"`
 }
 
 // GroupMemberAddTx contains data to add new member for group
@@ -109,7 +188,7 @@ type GroupMemberAddTx struct {
 
 // GroupCreate creates new group
 // Link: https://yandex.ru/dev/api360/doc/ref/GroupService/GroupService_Create.html
-func (ya *Ya360) GroupCreate(group GroupCreateTx) (GroupRx, error) {
+func (ya *Ya360) GroupCreate(ctx context.Context, group GroupCreateTx) (GroupRx, error) {
 
 	var (
 		resp GroupRx
@@ -122,12 +201,8 @@ func (ya *Ya360) GroupCreate(group GroupCreateTx) (GroupRx, error) {
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodPost, ur, group, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.alter(ctx, http.MethodPost, ur, group, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
@@ -135,7 +210,7 @@ func (ya *Ya360) GroupCreate(group GroupCreateTx) (GroupRx, error) {
 
 // GroupGet gets specified group
 // Link: https://yandex.ru/dev/api360/doc/ref/GroupService/GroupService_Get.html
-func (ya *Ya360) GroupGet(groupID int64) (GroupRx, error) {
+func (ya *Ya360) GroupGet(ctx context.Context, groupID int64) (GroupRx, error) {
 
 	var (
 		resp GroupRx
@@ -148,12 +223,8 @@ func (ya *Ya360) GroupGet(groupID int64) (GroupRx, error) {
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.get(ur, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.get(ctx, ur, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
@@ -161,7 +232,7 @@ func (ya *Ya360) GroupGet(groupID int64) (GroupRx, error) {
 
 // GroupsList gets groups list
 // Link: https://yandex.ru/dev/api360/doc/ref/GroupService/GroupService_List.html
-func (ya *Ya360) GroupsList(page, perPage int64) (GroupsRx, error) {
+func (ya *Ya360) GroupsList(ctx context.Context, page, perPage int64, opts ListOptions) (GroupsRx, error) {
 
 	var (
 		resp GroupsRx
@@ -171,26 +242,137 @@ func (ya *Ya360) GroupsList(page, perPage int64) (GroupsRx, error) {
 
 	urlParams.Add("page", strconv.FormatInt(page, 10))
 	urlParams.Add("perPage", strconv.FormatInt(perPage, 10))
+	opts.urlParams(urlParams)
 
 	ur := url.URL{
 		Path:     fmt.Sprintf("/directory/v1/org/%d/groups", ya.s.OrgID),
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.get(ur, &resp)
+	if err := ya.get(ctx, ur, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// GroupsPages walks through all groups list pages and calls `fn` for each of them.
+// Page fetch errors are passed into `fn`. Walking stops if `fn` returns an error
+// or context is done. If `workers` is greater than 1 pages after the first one are
+// fetched concurrently and `fn` may be called out of the pages order (but never concurrently)
+func (ya *Ya360) GroupsPages(ctx context.Context, perPage int64, opts ListOptions, workers int, fn func(page int64, groups GroupsRx, err error) error) error {
+	return pagesWalk(ctx, workers, func(ctx context.Context, page int64) (int64, func() error) {
+		l, err := ya.GroupsList(ctx, page, perPage, opts)
+		return l.Pages, func() error {
+			return fn(page, l, err)
+		}
+	})
+}
+
+// GroupsAll gets all groups from all pages of groups list
+func (ya *Ya360) GroupsAll(ctx context.Context, perPage int64, opts ListOptions, workers int) ([]GroupRx, error) {
+
+	pages := make(map[int64][]GroupRx)
+
+	err := ya.GroupsPages(ctx, perPage, opts, workers, func(page int64, groups GroupsRx, err error) error {
+		if err != nil {
+			return err
+		}
+		pages[page] = groups.Groups
+		return nil
+	})
 	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
+		return nil, err
+	}
+
+	groups := []GroupRx{}
+	for page := int64(1); page <= int64(len(pages)); page++ {
+		groups = append(groups, pages[page]...)
+	}
+
+	return groups, nil
+}
+
+// GroupsIterator iterates over groups lazily fetching groups list pages one by one
+type GroupsIterator struct {
+	ya      *Ya360
+	ctx     context.Context
+	perPage int64
+	opts    ListOptions
+
+	page   int64
+	pages  int64
+	groups []GroupRx
+	i      int
+	group  GroupRx
+	err    error
+}
+
+// GroupsIter creates iterator over all groups
+func (ya *Ya360) GroupsIter(ctx context.Context, perPage int64, opts ListOptions) *GroupsIterator {
+	return &GroupsIterator{
+		ya:      ya,
+		ctx:     ctx,
+		perPage: perPage,
+		opts:    opts,
+	}
+}
+
+// Next advances iterator to the next group. It returns false when
+// all groups have been iterated or an error occurred
+func (it *GroupsIterator) Next() bool {
+
+	for it.i >= len(it.groups) {
+
+		if it.err != nil || (it.page > 0 && it.page >= it.pages) {
+			return false
+		}
+
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		l, err := it.ya.GroupsList(it.ctx, it.page+1, it.perPage, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page++
+		it.pages = l.Pages
+		it.groups = l.Groups
+		it.i = 0
+
+		if len(it.groups) == 0 {
+			return false
 		}
 	}
 
-	return resp, nil
+	it.group = it.groups[it.i]
+	it.i++
+
+	return true
+}
+
+// Group returns current group
+func (it *GroupsIterator) Group() GroupRx {
+	return it.group
+}
+
+// Page returns number of the last fetched page
+func (it *GroupsIterator) Page() int64 {
+	return it.page
 }
 
-// GroupMembersList adds members list for specified group
+// Err returns error occurred during iteration
+func (it *GroupsIterator) Err() error {
+	return it.err
+}
+
+// GroupMembersList gets members list for specified group
 // Link: https://yandex.ru/dev/api360/doc/ref/GroupService/GroupService_ListMembers.html
-func (ya *Ya360) GroupMembersList(groupID int64) (GroupMembersListRx, error) {
+func (ya *Ya360) GroupMembersList(ctx context.Context, groupID int64) (GroupMembersListRx, error) {
 
 	var (
 		resp GroupMembersListRx
@@ -203,12 +385,8 @@ func (ya *Ya360) GroupMembersList(groupID int64) (GroupMembersListRx, error) {
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.get(ur, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.get(ctx, ur, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
@@ -216,7 +394,7 @@ func (ya *Ya360) GroupMembersList(groupID int64) (GroupMembersListRx, error) {
 
 // GroupUpdate updates specified group with new `group` data
 // Link: https://yandex.ru/dev/api360/doc/ref/GroupService/GroupService_Update.html
-func (ya *Ya360) GroupUpdate(groupID int64, group GroupUpdateTx) (GroupRx, error) {
+func (ya *Ya360) GroupUpdate(ctx context.Context, groupID int64, group GroupUpdateTx) (GroupRx, error) {
 
 	var (
 		resp GroupRx
@@ -229,12 +407,8 @@ func (ya *Ya360) GroupUpdate(groupID int64, group GroupUpdateTx) (GroupRx, error
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodPatch, ur, group, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.alter(ctx, http.MethodPatch, ur, group, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
@@ -242,7 +416,7 @@ func (ya *Ya360) GroupUpdate(groupID int64, group GroupUpdateTx) (GroupRx, error
 
 // GroupMemberAdd adds new member into specified group
 // Link: https://yandex.ru/dev/api360/doc/ref/GroupService/GroupService_AddMember.html
-func (ya *Ya360) GroupMemberAdd(groupID int64, member GroupMemberAddTx) (GroupMemberAddRx, error) {
+func (ya *Ya360) GroupMemberAdd(ctx context.Context, groupID int64, member GroupMemberAddTx) (GroupMemberAddRx, error) {
 
 	var (
 		resp GroupMemberAddRx
@@ -255,20 +429,263 @@ func (ya *Ya360) GroupMemberAdd(groupID int64, member GroupMemberAddTx) (GroupMe
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodPost, ur, member, &resp)
+	if err := ya.alter(ctx, http.MethodPost, ur, member, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// GroupMemberDelete deletes member from specified group
+// Link: https://yandex.ru/dev/api360/doc/ref/GroupService/GroupService_DeleteMember.html
+func (ya *Ya360) GroupMemberDelete(ctx context.Context, groupID int64, memberType MemberType, memberID string) (GroupMemberDeleteRx, error) {
+
+	var (
+		resp GroupMemberDeleteRx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/directory/v1/org/%d/groups/%d/members/%s/%s", ya.s.OrgID, groupID, memberType, memberID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.alter(ctx, http.MethodDelete, ur, nil, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// GroupMembersDeleteAll deletes all members from specified group
+// Link: https://yandex.ru/dev/api360/doc/ref/GroupService/GroupService_DeleteAllMembers.html
+func (ya *Ya360) GroupMembersDeleteAll(ctx context.Context, groupID int64) (GroupMembersDeleteAllRx, error) {
+
+	var (
+		resp GroupMembersDeleteAllRx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/directory/v1/org/%d/groups/%d/members", ya.s.OrgID, groupID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.alter(ctx, http.MethodDelete, ur, nil, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// GroupSetMembers makes members of specified group to be equal to `desired` list.
+// It computes difference with the current members and adds or deletes members
+// one by one using up to `workers` concurrent requests. Unlike `GroupUpdate` it
+// doesn't affect members added or deleted by others and not mentioned in the difference.
+// If some operations fail, the report contains them and the error is returned
+func (ya *Ya360) GroupSetMembers(ctx context.Context, groupID int64, desired []MemberIDType, workers int) (GroupSetMembersReport, error) {
+
+	var (
+		report GroupSetMembersReport
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+	)
+
+	l, err := ya.GroupMembersList(ctx, groupID)
 	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
+		return report, err
+	}
+
+	add, del := membersDiff(l.Members(), desired)
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	sem := make(chan struct{}, workers)
+
+	op := func(o GroupSetMembersOp, m MemberIDType) {
+
+		defer wg.Done()
+		defer func() { <-sem }()
+
+		var err error
+
+		switch o {
+		case GroupSetMembersOpAdd:
+			_, err = ya.GroupMemberAdd(ctx, groupID, GroupMemberAddTx{
+				ID:   m.ID,
+				Type: m.Type,
+			})
+		case GroupSetMembersOpDelete:
+			_, err = ya.GroupMemberDelete(ctx, groupID, m.Type, m.ID)
 		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil {
+			report.Failed = append(report.Failed, GroupSetMembersFailure{
+				Member: m,
+				Op:     o,
+				Err:    err,
+			})
+			return
+		}
+
+		switch o {
+		case GroupSetMembersOpAdd:
+			report.Added = append(report.Added, m)
+		case GroupSetMembersOpDelete:
+			report.Deleted = append(report.Deleted, m)
+		}
+	}
+
+	for _, m := range del {
+		sem <- struct{}{}
+		wg.Add(1)
+		go op(GroupSetMembersOpDelete, m)
+	}
+
+	for _, m := range add {
+		sem <- struct{}{}
+		wg.Add(1)
+		go op(GroupSetMembersOpAdd, m)
+	}
+
+	wg.Wait()
+
+	if len(report.Failed) > 0 {
+		return report, fmt.Errorf("group set members: %d of %d operations failed, first error: %w", len(report.Failed), len(add)+len(del), report.Failed[0].Err)
+	}
+
+	return report, nil
+}
+
+// GroupAdminsList gets admins list for specified group
+// Link: https://yandex.ru/dev/api360/doc/ref/GroupService/GroupService_ListAdmins.html
+func (ya *Ya360) GroupAdminsList(ctx context.Context, groupID int64) (GroupAdminsRx, error) {
+
+	var (
+		resp GroupAdminsRx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/directory/v1/org/%d/groups/%d/admins", ya.s.OrgID, groupID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.get(ctx, ur, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
 }
 
+// GroupAdminsUpdate replaces admins of specified group with new `admins`
+// Link: https://yandex.ru/dev/api360/doc/ref/GroupService/GroupService_UpdateAdmins.html
+func (ya *Ya360) GroupAdminsUpdate(ctx context.Context, groupID int64, admins GroupAdminsUpdateTx) (GroupAdminsUpdateRx, error) {
+
+	var (
+		resp GroupAdminsUpdateRx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/directory/v1/org/%d/groups/%d/admins", ya.s.OrgID, groupID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if admins.AdminIDs == nil {
+		admins.AdminIDs = []string{}
+	}
+
+	if err := ya.alter(ctx, http.MethodPut, ur, admins, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// GroupAdminsDelete deletes all admins from specified group
+// Link: https://yandex.ru/dev/api360/doc/ref/GroupService/GroupService_DeleteAdmins.html
+func (ya *Ya360) GroupAdminsDelete(ctx context.Context, groupID int64) (GroupAdminsDeleteRx, error) {
+
+	var (
+		resp GroupAdminsDeleteRx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/directory/v1/org/%d/groups/%d/admins", ya.s.OrgID, groupID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.alter(ctx, http.MethodDelete, ur, nil, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// GroupAdminAdd adds user into admins of specified group keeping other admins
+func (ya *Ya360) GroupAdminAdd(ctx context.Context, groupID int64, userID string) (GroupAdminsUpdateRx, error) {
+
+	a, err := ya.GroupAdminsList(ctx, groupID)
+	if err != nil {
+		return GroupAdminsUpdateRx{}, err
+	}
+
+	for _, id := range a.AdminIDs {
+		if id == userID {
+			return GroupAdminsUpdateRx{
+				AdminIDs: a.AdminIDs,
+				GroupID:  groupID,
+			}, nil
+		}
+	}
+
+	return ya.GroupAdminsUpdate(ctx, groupID, GroupAdminsUpdateTx{
+		AdminIDs: append(a.AdminIDs, userID),
+	})
+}
+
+// GroupAdminDelete deletes user from admins of specified group keeping other admins
+func (ya *Ya360) GroupAdminDelete(ctx context.Context, groupID int64, userID string) (GroupAdminsUpdateRx, error) {
+
+	a, err := ya.GroupAdminsList(ctx, groupID)
+	if err != nil {
+		return GroupAdminsUpdateRx{}, err
+	}
+
+	ids := []string{}
+	for _, id := range a.AdminIDs {
+		if id != userID {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == len(a.AdminIDs) {
+		return GroupAdminsUpdateRx{
+			AdminIDs: a.AdminIDs,
+			GroupID:  groupID,
+		}, nil
+	}
+
+	return ya.GroupAdminsUpdate(ctx, groupID, GroupAdminsUpdateTx{
+		AdminIDs: ids,
+	})
+}
+
 // GroupDelete deletes group
 // Link: https://yandex.ru/dev/api360/doc/ref/GroupService/GroupService_Delete.html
-func (ya *Ya360) GroupDelete(groupID int64) (GroupDeleteRx, error) {
+func (ya *Ya360) GroupDelete(ctx context.Context, groupID int64) (GroupDeleteRx, error) {
 
 	var (
 		resp GroupDeleteRx
@@ -281,13 +698,66 @@ func (ya *Ya360) GroupDelete(groupID int64) (GroupDeleteRx, error) {
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodDelete, ur, nil, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.alter(ctx, http.MethodDelete, ur, nil, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
 }
+
+// Members returns all group members as a flat list
+func (l GroupMembersListRx) Members() []MemberIDType {
+
+	m := []MemberIDType{}
+
+	for _, e := range l.Users {
+		m = append(m, MemberIDType{
+			ID:   e.ID,
+			Type: MemberTypeUser,
+		})
+	}
+
+	for _, e := range l.Groups {
+		m = append(m, MemberIDType{
+			ID:   strconv.FormatInt(e.ID, 10),
+			Type: MemberTypeGroup,
+		})
+	}
+
+	for _, e := range l.Departments {
+		m = append(m, MemberIDType{
+			ID:   strconv.FormatInt(e.ID, 10),
+			Type: MemberTypeDepartment,
+		})
+	}
+
+	return m
+}
+
+// membersDiff returns members to be added to and deleted from `current` to get `desired`
+func membersDiff(current, desired []MemberIDType) ([]MemberIDType, []MemberIDType) {
+
+	var add, del []MemberIDType
+
+	c := make(map[MemberIDType]bool)
+	for _, m := range current {
+		c[m] = true
+	}
+
+	d := make(map[MemberIDType]bool)
+	for _, m := range desired {
+		if c[m] == false && d[m] == false {
+			add = append(add, m)
+		}
+		d[m] = true
+	}
+
+	for _, m := range current {
+		if d[m] == false {
+			del = append(del, m)
+			d[m] = true
+		}
+	}
+
+	return add, del
+}
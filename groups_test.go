@@ -1,8 +1,7 @@
 package ya360
 
 import (
-	"os"
-	"strconv"
+	"context"
 	"testing"
 )
 
@@ -15,19 +14,7 @@ var (
 
 func TestGroupsCRUD(t *testing.T) {
 
-	oAuth := os.Getenv("YA360_OAUTH")
-	orgID, err := strconv.ParseInt(os.Getenv("YA360_ORG_ID"), 10, 64)
-	if err != nil {
-		t.Fatal("Init error: make sure environment variable `YA360_ORG_ID` correctly defined:", err)
-	}
-	if len(oAuth) == 0 {
-		t.Fatal("Init error: make sure environment variable `YA360_OAUTH` correctly defined")
-	}
-
-	y := Init(Settings{
-		OAuth: oAuth,
-		OrgID: orgID,
-	})
+	y := testInit(t)
 
 	gCreated := testGroupCreate(t, y)
 	defer testGroupDetele(t, y, gCreated.ID)
@@ -41,12 +28,27 @@ func TestGroupsCRUD(t *testing.T) {
 	testGroupUpdate(t, y, gCreated.ID)
 
 	testGroupMemberAdd(t, y, gCreated.ID, uCreated.ID)
-	testGroupMemberDel(t, y, gCreated.ID)
+	testGroupMembersList(t, y, gCreated.ID, uCreated.ID)
+	testGroupMemberDel(t, y, gCreated.ID, uCreated.ID)
+
+	testGroupMemberAdd(t, y, gCreated.ID, uCreated.ID)
+	testGroupMembersDelAll(t, y, gCreated.ID, uCreated.ID)
+
+	testGroupMemberAdd(t, y, gCreated.ID, uCreated.ID)
+	testGroupUpdateMembersClear(t, y, gCreated.ID)
+
+	testGroupAdminAdd(t, y, gCreated.ID, uCreated.ID)
+	testGroupAdminsList(t, y, gCreated.ID, uCreated.ID)
+	testGroupAdminDel(t, y, gCreated.ID, uCreated.ID)
+	testGroupAdminsUpdate(t, y, gCreated.ID, uCreated.ID)
+	testGroupAdminsDel(t, y, gCreated.ID)
+
+	testGroupSetMembers(t, y, gCreated.ID, uCreated.ID)
 }
 
 func testGroupCreate(t *testing.T, y Ya360) GroupRx {
 
-	g, err := y.GroupCreate(GroupCreateTx{
+	g, err := y.GroupCreate(context.Background(), GroupCreateTx{
 		Name:        testGroupName,
 		Description: testGroupDescription,
 	})
@@ -65,7 +67,7 @@ func testGroupCreate(t *testing.T, y Ya360) GroupRx {
 
 func testGroupGet(t *testing.T, y Ya360, groupID int64) {
 
-	g, err := y.GroupGet(groupID)
+	g, err := y.GroupGet(context.Background(), groupID)
 	if err != nil {
 		t.Fatal("Group get error:", err)
 	}
@@ -83,7 +85,7 @@ func testGroupGet(t *testing.T, y Ya360, groupID int64) {
 
 func testGroupsList(t *testing.T, y Ya360) {
 
-	g, err := y.GroupsList(1, 1000)
+	g, err := y.GroupsList(context.Background(), 1, 1000, ListOptions{})
 	if err != nil {
 		t.Fatal("Groups list error:", err)
 	}
@@ -100,8 +102,8 @@ func testGroupsList(t *testing.T, y Ya360) {
 
 func testGroupUpdate(t *testing.T, y Ya360, groupID int64) {
 
-	g, err := y.GroupUpdate(groupID, GroupUpdateTx{
-		Name: testGroupUpdatedName,
+	g, err := y.GroupUpdate(context.Background(), groupID, GroupUpdateTx{
+		Name: SetString(testGroupUpdatedName),
 	})
 	if err != nil {
 		t.Fatal("Group update error:", err)
@@ -116,7 +118,7 @@ func testGroupUpdate(t *testing.T, y Ya360, groupID int64) {
 
 func testGroupMemberAdd(t *testing.T, y Ya360, groupID int64, userID string) {
 
-	g, err := y.GroupMemberAdd(groupID, GroupMemberAddTx{
+	g, err := y.GroupMemberAdd(context.Background(), groupID, GroupMemberAddTx{
 		ID:   userID,
 		Type: MemberTypeUser,
 	})
@@ -131,25 +133,202 @@ func testGroupMemberAdd(t *testing.T, y Ya360, groupID int64, userID string) {
 	t.Logf("Group add member: success")
 }
 
-func testGroupMemberDel(t *testing.T, y Ya360, groupID int64) {
+func testGroupMembersList(t *testing.T, y Ya360, groupID int64, userID string) {
 
-	g, err := y.GroupUpdate(groupID, GroupUpdateTx{
-		Members: []MemberIDType{},
-	})
+	g, err := y.GroupMembersList(context.Background(), groupID)
+	if err != nil {
+		t.Fatal("Group members list error:", err)
+	}
+
+	if len(g.Users) != 1 || g.Users[0].ID != userID || g.Users[0].Nickname != testUserNickame {
+		t.Fatalf("Group members list error: incorrect users (returned: %+v)", g.Users)
+	}
+
+	m := g.Members()
+	if len(m) != 1 || m[0].ID != userID || m[0].Type != MemberTypeUser {
+		t.Fatalf("Group members list error: incorrect members (returned: %v)", m)
+	}
+
+	t.Logf("Group members list: success")
+}
+
+func testGroupMemberDel(t *testing.T, y Ya360, groupID int64, userID string) {
+
+	g, err := y.GroupMemberDelete(context.Background(), groupID, MemberTypeUser, userID)
 	if err != nil {
 		t.Fatal("Group delete member error:", err)
 	}
 
-	if len(g.Members) > 0 {
-		t.Fatalf("Group delete member error: incorrect members count (returned: %d)", len(g.Members))
+	if g.Deleted == false || g.ID != userID || g.Type != MemberTypeUser {
+		t.Fatalf("Group delete member error: incorrect deleted member (returned: %t, %s, %s)", g.Deleted, g.ID, g.Type)
 	}
 
 	t.Logf("Group delete member: success")
 }
 
+func testGroupMembersDelAll(t *testing.T, y Ya360, groupID int64, userID string) {
+
+	g, err := y.GroupMembersDeleteAll(context.Background(), groupID)
+	if err != nil {
+		t.Fatal("Group delete all members error:", err)
+	}
+
+	if len(g.Users) != 1 || g.Users[0].ID != userID {
+		t.Fatalf("Group delete all members error: incorrect deleted users (returned: %v)", g.Users)
+	}
+
+	gr, err := y.GroupGet(context.Background(), groupID)
+	if err != nil {
+		t.Fatal("Group delete all members error:", err)
+	}
+
+	if len(gr.Members) > 0 {
+		t.Fatalf("Group delete all members error: incorrect members count (returned: %d)", len(gr.Members))
+	}
+
+	t.Logf("Group delete all members: success")
+}
+
+func testGroupUpdateMembersClear(t *testing.T, y Ya360, groupID int64) {
+
+	g, err := y.GroupUpdate(context.Background(), groupID, GroupUpdateTx{
+		Members: &[]MemberIDType{},
+	})
+	if err != nil {
+		t.Fatal("Group update members clear error:", err)
+	}
+
+	if len(g.Members) > 0 || g.Name != testGroupUpdatedName {
+		t.Fatalf("Group update members clear error: incorrect members count or name (returned: %d, %s)", len(g.Members), g.Name)
+	}
+
+	t.Logf("Group update members clear: success")
+}
+
+func testGroupAdminAdd(t *testing.T, y Ya360, groupID int64, userID string) {
+
+	g, err := y.GroupAdminAdd(context.Background(), groupID, userID)
+	if err != nil {
+		t.Fatal("Group add admin error:", err)
+	}
+
+	if len(g.AdminIDs) != 1 || g.AdminIDs[0] != userID {
+		t.Fatalf("Group add admin error: incorrect admins (returned: %v)", g.AdminIDs)
+	}
+
+	t.Logf("Group add admin: success")
+}
+
+func testGroupAdminsList(t *testing.T, y Ya360, groupID int64, userID string) {
+
+	g, err := y.GroupAdminsList(context.Background(), groupID)
+	if err != nil {
+		t.Fatal("Group admins list error:", err)
+	}
+
+	for _, id := range g.AdminIDs {
+		if id == userID {
+			t.Logf("Group admins list: success")
+			return
+		}
+	}
+
+	t.Fatal("Group admins list error: added admin not found")
+}
+
+func testGroupAdminDel(t *testing.T, y Ya360, groupID int64, userID string) {
+
+	g, err := y.GroupAdminDelete(context.Background(), groupID, userID)
+	if err != nil {
+		t.Fatal("Group delete admin error:", err)
+	}
+
+	if len(g.AdminIDs) > 0 {
+		t.Fatalf("Group delete admin error: incorrect admins (returned: %v)", g.AdminIDs)
+	}
+
+	t.Logf("Group delete admin: success")
+}
+
+func testGroupAdminsUpdate(t *testing.T, y Ya360, groupID int64, userID string) {
+
+	g, err := y.GroupAdminsUpdate(context.Background(), groupID, GroupAdminsUpdateTx{
+		AdminIDs: []string{userID},
+	})
+	if err != nil {
+		t.Fatal("Group update admins error:", err)
+	}
+
+	if g.GroupID != groupID || len(g.AdminIDs) != 1 || g.AdminIDs[0] != userID {
+		t.Fatalf("Group update admins error: incorrect admins (returned: %d, %v)", g.GroupID, g.AdminIDs)
+	}
+
+	t.Logf("Group update admins: success")
+}
+
+func testGroupAdminsDel(t *testing.T, y Ya360, groupID int64) {
+
+	g, err := y.GroupAdminsDelete(context.Background(), groupID)
+	if err != nil {
+		t.Fatal("Group delete admins error:", err)
+	}
+
+	if g.Deleted == false || g.GroupID != groupID {
+		t.Fatalf("Group delete admins error: incorrect result (returned: %t, %d)", g.Deleted, g.GroupID)
+	}
+
+	a, err := y.GroupAdminsList(context.Background(), groupID)
+	if err != nil {
+		t.Fatal("Group delete admins error:", err)
+	}
+
+	if len(a.AdminIDs) > 0 {
+		t.Fatalf("Group delete admins error: incorrect admins count (returned: %d)", len(a.AdminIDs))
+	}
+
+	t.Logf("Group delete admins: success")
+}
+
+func testGroupSetMembers(t *testing.T, y Ya360, groupID int64, userID string) {
+
+	u := MemberIDType{
+		ID:   userID,
+		Type: MemberTypeUser,
+	}
+
+	r, err := y.GroupSetMembers(context.Background(), groupID, []MemberIDType{u}, 2)
+	if err != nil {
+		t.Fatal("Group set members error:", err)
+	}
+
+	if len(r.Added) != 1 || r.Added[0] != u || len(r.Deleted) > 0 {
+		t.Fatalf("Group set members error: incorrect report (returned: %+v)", r)
+	}
+
+	r, err = y.GroupSetMembers(context.Background(), groupID, []MemberIDType{{ID: "0", Type: MemberTypeUser}}, 2)
+	if err == nil {
+		t.Fatal("Group set members error: error expected for nonexistent member")
+	}
+
+	if len(r.Deleted) != 1 || r.Deleted[0] != u || len(r.Failed) != 1 || r.Failed[0].Op != GroupSetMembersOpAdd {
+		t.Fatalf("Group set members error: incorrect report (returned: %+v)", r)
+	}
+
+	g, err := y.GroupGet(context.Background(), groupID)
+	if err != nil {
+		t.Fatal("Group set members error:", err)
+	}
+
+	if len(g.Members) > 0 {
+		t.Fatalf("Group set members error: incorrect members count (returned: %d)", len(g.Members))
+	}
+
+	t.Logf("Group set members: success")
+}
+
 func testGroupDetele(t *testing.T, y Ya360, groupID int64) {
 
-	g, err := y.GroupDelete(groupID)
+	g, err := y.GroupDelete(context.Background(), groupID)
 	if err != nil {
 		t.Fatal("Group delete error:", err)
 	}
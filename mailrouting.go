@@ -0,0 +1,354 @@
+package ya360
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+)
+
+// MailRoutingRulesRx contains organization mail routing rules
+type MailRoutingRulesRx struct {
+	Rules []MailRoutingRule `json:"rules"`
+}
+
+// MailRoutingRulesTx contains data to replace organization mail routing rules
+type MailRoutingRulesTx struct {
+	Rules []MailRoutingRule `json:"rules"`
+}
+
+// MailRoutingRule contains a mail routing rule. Rules are applied
+// in order, rule with `Terminal` set stops processing of the next rules
+type MailRoutingRule struct {
+	Terminal  bool                 `json:"terminal"`
+	Condition MailRoutingCondition `json:"condition"`
+	Actions   []MailRoutingAction  `json:"actions"`
+	Scope     MailRoutingScope     `json:"scope"`
+}
+
+// MailRoutingScope contains mail direction the rule is applied to
+type MailRoutingScope struct {
+	Direction MailRoutingDirection `json:"direction"`
+}
+
+// MailRoutingAction contains action applied to the mail matching the rule condition
+type MailRoutingAction struct {
+	Action MailRoutingActionType  `json:"action"`
+	Data   *MailRoutingActionData `json:"data,omitempty"`
+}
+
+// MailRoutingActionData contains action parameters
+type MailRoutingActionData struct {
+	Email string `json:"email,omitempty"`
+}
+
+// MailRoutingCondition contains condition of the mail routing rule. The condition is
+// either a comparison of the mail `Field` with the `Value`, or a combination of the
+// nested conditions with `And` or `Or`. Empty condition matches all mail
+type MailRoutingCondition struct {
+	Field MailRoutingField
+	Op    MailRoutingOp
+	Value string
+
+	And []MailRoutingCondition
+	Or  []MailRoutingCondition
+}
+
+type MailRoutingDirection string
+
+const (
+	MailRoutingDirectionInbound  MailRoutingDirection = "inbound"
+	MailRoutingDirectionOutbound MailRoutingDirection = "outbound"
+)
+
+func (d MailRoutingDirection) String() string {
+	return string(d)
+}
+
+type MailRoutingActionType string
+
+const (
+	MailRoutingActionDrop     MailRoutingActionType = "drop"
+	MailRoutingActionForward  MailRoutingActionType = "forward"
+	MailRoutingActionRedirect MailRoutingActionType = "redirect"
+)
+
+func (a MailRoutingActionType) String() string {
+	return string(a)
+}
+
+type MailRoutingField string
+
+const (
+	MailRoutingFieldFrom MailRoutingField = "address:from"
+	MailRoutingFieldTo   MailRoutingField = "address:to"
+)
+
+func (f MailRoutingField) String() string {
+	return string(f)
+}
+
+type MailRoutingOp string
+
+const (
+	MailRoutingOpEq MailRoutingOp = "$eq"
+	MailRoutingOpNe MailRoutingOp = "$ne"
+)
+
+func (o MailRoutingOp) String() string {
+	return string(o)
+}
+
+const (
+	mailRoutingCondAnd = "$and"
+	mailRoutingCondOr  = "$or"
+)
+
+// MailRoutingConditionMatch returns condition comparing the mail field with the value
+func MailRoutingConditionMatch(field MailRoutingField, op MailRoutingOp, value string) MailRoutingCondition {
+	return MailRoutingCondition{
+		Field: field,
+		Op:    op,
+		Value: value,
+	}
+}
+
+// MailRoutingConditionAnd returns condition matching mail if all of conditions match
+func MailRoutingConditionAnd(conditions ...MailRoutingCondition) MailRoutingCondition {
+	return MailRoutingCondition{
+		And: conditions,
+	}
+}
+
+// MailRoutingConditionOr returns condition matching mail if any of conditions match
+func MailRoutingConditionOr(conditions ...MailRoutingCondition) MailRoutingCondition {
+	return MailRoutingCondition{
+		Or: conditions,
+	}
+}
+
+// MarshalJSON encodes condition into Yandex 360 format,
+// e.g. `{"address:from":{"$eq":"user@example.com"}}`
+func (c MailRoutingCondition) MarshalJSON() ([]byte, error) {
+
+	switch {
+	case c.And != nil:
+		return json.Marshal(map[string][]MailRoutingCondition{mailRoutingCondAnd: c.And})
+	case c.Or != nil:
+		return json.Marshal(map[string][]MailRoutingCondition{mailRoutingCondOr: c.Or})
+	case len(c.Field) > 0:
+		op := c.Op
+		if len(op) == 0 {
+			op = MailRoutingOpEq
+		}
+		return json.Marshal(map[MailRoutingField]map[MailRoutingOp]string{c.Field: {op: c.Value}})
+	}
+
+	return []byte(`{}`), nil
+}
+
+// UnmarshalJSON decodes condition from Yandex 360 format. Object with several
+// fields is decoded as `And` combination of the conditions sorted by field.
+// Field compared with a plain string is decoded with `MailRoutingOpEq` operation
+func (c *MailRoutingCondition) UnmarshalJSON(data []byte) error {
+
+	m := make(map[string]json.RawMessage)
+
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	*c = MailRoutingCondition{}
+
+	if len(m) == 0 {
+		return nil
+	}
+
+	if len(m) > 1 {
+
+		keys := []string{}
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		c.And = []MailRoutingCondition{}
+		for _, k := range keys {
+			e := MailRoutingCondition{}
+			if err := e.UnmarshalJSON([]byte(fmt.Sprintf(`{%q:%s}`, k, m[k]))); err != nil {
+				return err
+			}
+			c.And = append(c.And, e)
+		}
+
+		return nil
+	}
+
+	for k, v := range m {
+
+		switch k {
+		case mailRoutingCondAnd:
+			c.And = []MailRoutingCondition{}
+			return json.Unmarshal(v, &c.And)
+		case mailRoutingCondOr:
+			c.Or = []MailRoutingCondition{}
+			return json.Unmarshal(v, &c.Or)
+		}
+
+		c.Field = MailRoutingField(k)
+
+		// Plain value means equality
+		if err := json.Unmarshal(v, &c.Value); err == nil {
+			c.Op = MailRoutingOpEq
+			return nil
+		}
+
+		ops := make(map[MailRoutingOp]string)
+		if err := json.Unmarshal(v, &ops); err != nil {
+			return fmt.Errorf("can't decode condition for field `%s`: %v", k, err)
+		}
+
+		if len(ops) != 1 {
+			return fmt.Errorf("condition for field `%s` must contain exactly one operation", k)
+		}
+
+		for o, e := range ops {
+			c.Op = o
+			c.Value = e
+		}
+	}
+
+	return nil
+}
+
+// MailRoutingRulesGet gets organization mail routing rules
+// Link: https://yandex.ru/dev/api360/doc/ref/RoutingService/RoutingService_GetRules.html
+func (ya *Ya360) MailRoutingRulesGet(ctx context.Context) (MailRoutingRulesRx, error) {
+
+	var (
+		resp MailRoutingRulesRx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/admin/v1/org/%d/mail/routing/rules", ya.s.OrgID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.get(ctx, ur, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// MailRoutingRulesSet replaces all organization mail routing rules with `rules`.
+// Yandex 360 returns an empty response, so the set rules are returned
+// Link: https://yandex.ru/dev/api360/doc/ref/RoutingService/RoutingService_SetRules.html
+func (ya *Ya360) MailRoutingRulesSet(ctx context.Context, rules MailRoutingRulesTx) (MailRoutingRulesRx, error) {
+
+	var (
+		resp MailRoutingRulesRx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/admin/v1/org/%d/mail/routing/rules", ya.s.OrgID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if rules.Rules == nil {
+		rules.Rules = []MailRoutingRule{}
+	}
+
+	if err := ya.alter(ctx, http.MethodPut, ur, rules, nil); err != nil {
+		return resp, err
+	}
+
+	resp.Rules = rules.Rules
+
+	return resp, nil
+}
+
+// MailRoutingRuleInsert inserts rule into organization mail routing rules at the
+// specified position keeping other rules. Rule is appended if `position` is
+// negative or exceeds rules count. Note that rules are read and replaced with
+// separate requests, so changes made by others in between are overwritten
+func (ya *Ya360) MailRoutingRuleInsert(ctx context.Context, position int, rule MailRoutingRule) (MailRoutingRulesRx, error) {
+
+	r, err := ya.MailRoutingRulesGet(ctx)
+	if err != nil {
+		return r, err
+	}
+
+	if position < 0 || position > len(r.Rules) {
+		position = len(r.Rules)
+	}
+
+	rules := append([]MailRoutingRule{}, r.Rules[:position]...)
+	rules = append(rules, rule)
+	rules = append(rules, r.Rules[position:]...)
+
+	return ya.MailRoutingRulesSet(ctx, MailRoutingRulesTx{
+		Rules: rules,
+	})
+}
+
+// MailRoutingRuleRemove removes the first rule equal to the `rule` from organization
+// mail routing rules keeping other rules. Error matching `ErrNotFound` is returned
+// if there is no such rule. Note that rules are read and replaced with separate
+// requests, so changes made by others in between are overwritten
+func (ya *Ya360) MailRoutingRuleRemove(ctx context.Context, rule MailRoutingRule) (MailRoutingRulesRx, error) {
+
+	r, err := ya.MailRoutingRulesGet(ctx)
+	if err != nil {
+		return r, err
+	}
+
+	for i, e := range r.Rules {
+
+		eq, err := mailRoutingRuleEqual(e, rule)
+		if err != nil {
+			return r, err
+		}
+
+		if eq == true {
+			rules := append([]MailRoutingRule{}, r.Rules[:i]...)
+			rules = append(rules, r.Rules[i+1:]...)
+			return ya.MailRoutingRulesSet(ctx, MailRoutingRulesTx{
+				Rules: rules,
+			})
+		}
+	}
+
+	return r, fmt.Errorf("mail routing rule: %w", ErrNotFound)
+}
+
+// mailRoutingRuleEqual compares rules by their JSON representation
+func mailRoutingRuleEqual(a, b MailRoutingRule) (bool, error) {
+
+	var ai, bi interface{}
+
+	for _, e := range []struct {
+		r MailRoutingRule
+		i *interface{}
+	}{{a, &ai}, {b, &bi}} {
+		if e.r.Actions == nil {
+			e.r.Actions = []MailRoutingAction{}
+		}
+		s, err := json.Marshal(e.r)
+		if err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal(s, e.i); err != nil {
+			return false, err
+		}
+	}
+
+	return reflect.DeepEqual(ai, bi), nil
+}
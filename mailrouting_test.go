@@ -0,0 +1,177 @@
+package ya360
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var (
+	testMailRoutingRuleDrop = MailRoutingRule{
+		Terminal:  true,
+		Condition: MailRoutingConditionMatch(MailRoutingFieldFrom, MailRoutingOpEq, "spam@example.org"),
+		Actions: []MailRoutingAction{
+			{Action: MailRoutingActionDrop},
+		},
+		Scope: MailRoutingScope{
+			Direction: MailRoutingDirectionInbound,
+		},
+	}
+
+	testMailRoutingRuleForward = MailRoutingRule{
+		Condition: MailRoutingConditionOr(
+			MailRoutingConditionMatch(MailRoutingFieldTo, MailRoutingOpEq, "sales@example.com"),
+			MailRoutingConditionMatch(MailRoutingFieldTo, MailRoutingOpEq, "info@example.com"),
+		),
+		Actions: []MailRoutingAction{
+			{
+				Action: MailRoutingActionForward,
+				Data: &MailRoutingActionData{
+					Email: "archive@example.com",
+				},
+			},
+		},
+		Scope: MailRoutingScope{
+			Direction: MailRoutingDirectionInbound,
+		},
+	}
+)
+
+func TestMailRoutingRules(t *testing.T) {
+
+	y := testInit(t)
+
+	r := testMailRoutingRulesGet(t, y)
+	defer testMailRoutingRulesSet(t, y, r.Rules)
+
+	testMailRoutingRulesSet(t, y, []MailRoutingRule{})
+	testMailRoutingRuleInsert(t, y)
+	testMailRoutingRuleRemove(t, y)
+}
+
+func TestMailRoutingConditionJSON(t *testing.T) {
+
+	for _, c := range []struct {
+		cond MailRoutingCondition
+		json string
+	}{
+		{MailRoutingCondition{}, `{}`},
+		{MailRoutingConditionMatch(MailRoutingFieldFrom, MailRoutingOpNe, "a@example.com"), `{"address:from":{"$ne":"a@example.com"}}`},
+		{
+			MailRoutingConditionAnd(
+				MailRoutingConditionMatch(MailRoutingFieldFrom, MailRoutingOpEq, "a@example.com"),
+				MailRoutingConditionMatch(MailRoutingFieldTo, MailRoutingOpEq, "b@example.com"),
+			),
+			`{"$and":[{"address:from":{"$eq":"a@example.com"}},{"address:to":{"$eq":"b@example.com"}}]}`,
+		},
+	} {
+
+		b, err := json.Marshal(c.cond)
+		if err != nil {
+			t.Fatal("Mail routing condition marshal error:", err)
+		}
+
+		if string(b) != c.json {
+			t.Fatalf("Mail routing condition marshal error: incorrect JSON (expected: %s, returned: %s)", c.json, b)
+		}
+
+		d := MailRoutingCondition{}
+		if err := json.Unmarshal(b, &d); err != nil {
+			t.Fatal("Mail routing condition unmarshal error:", err)
+		}
+
+		if reflect.DeepEqual(c.cond, d) == false {
+			t.Fatalf("Mail routing condition unmarshal error: incorrect condition (returned: %v)", d)
+		}
+	}
+
+	d := MailRoutingCondition{}
+	if err := json.Unmarshal([]byte(`{"address:to":"b@example.com","address:from":{"$eq":"a@example.com"}}`), &d); err != nil {
+		t.Fatal("Mail routing condition unmarshal error:", err)
+	}
+
+	if len(d.And) != 2 || d.And[0].Field != MailRoutingFieldFrom || d.And[1].Op != MailRoutingOpEq || d.And[1].Value != "b@example.com" {
+		t.Fatalf("Mail routing condition unmarshal error: incorrect condition (returned: %v)", d)
+	}
+
+	t.Logf("Mail routing condition JSON: success")
+}
+
+func testMailRoutingRulesGet(t *testing.T, y Ya360) MailRoutingRulesRx {
+
+	r, err := y.MailRoutingRulesGet(context.Background())
+	if err != nil {
+		t.Fatal("Mail routing rules get error:", err)
+	}
+
+	t.Logf("Mail routing rules get: success")
+
+	return r
+}
+
+func testMailRoutingRulesSet(t *testing.T, y Ya360, rules []MailRoutingRule) {
+
+	if _, err := y.MailRoutingRulesSet(context.Background(), MailRoutingRulesTx{
+		Rules: rules,
+	}); err != nil {
+		t.Fatal("Mail routing rules set error:", err)
+	}
+
+	r, err := y.MailRoutingRulesGet(context.Background())
+	if err != nil {
+		t.Fatal("Mail routing rules set error:", err)
+	}
+
+	if len(r.Rules) != len(rules) {
+		t.Fatalf("Mail routing rules set error: incorrect rules count (returned: %d)", len(r.Rules))
+	}
+
+	t.Logf("Mail routing rules set: success")
+}
+
+func testMailRoutingRuleInsert(t *testing.T, y Ya360) {
+
+	if _, err := y.MailRoutingRuleInsert(context.Background(), -1, testMailRoutingRuleForward); err != nil {
+		t.Fatal("Mail routing rule insert error:", err)
+	}
+
+	if _, err := y.MailRoutingRuleInsert(context.Background(), 0, testMailRoutingRuleDrop); err != nil {
+		t.Fatal("Mail routing rule insert error:", err)
+	}
+
+	r, err := y.MailRoutingRulesGet(context.Background())
+	if err != nil {
+		t.Fatal("Mail routing rule insert error:", err)
+	}
+
+	if len(r.Rules) != 2 ||
+		reflect.DeepEqual(r.Rules[0], testMailRoutingRuleDrop) == false ||
+		reflect.DeepEqual(r.Rules[1], testMailRoutingRuleForward) == false {
+		t.Fatalf("Mail routing rule insert error: incorrect rules (returned: %v)", r.Rules)
+	}
+
+	t.Logf("Mail routing rule insert: success")
+}
+
+func testMailRoutingRuleRemove(t *testing.T, y Ya360) {
+
+	if _, err := y.MailRoutingRuleRemove(context.Background(), testMailRoutingRuleDrop); err != nil {
+		t.Fatal("Mail routing rule remove error:", err)
+	}
+
+	r, err := y.MailRoutingRulesGet(context.Background())
+	if err != nil {
+		t.Fatal("Mail routing rule remove error:", err)
+	}
+
+	if len(r.Rules) != 1 || reflect.DeepEqual(r.Rules[0], testMailRoutingRuleForward) == false {
+		t.Fatalf("Mail routing rule remove error: incorrect rules (returned: %v)", r.Rules)
+	}
+
+	if _, err := y.MailRoutingRuleRemove(context.Background(), testMailRoutingRuleDrop); IsNotFound(err) == false {
+		t.Fatal("Mail routing rule remove error: not found error expected, got:", err)
+	}
+
+	t.Logf("Mail routing rule remove: success")
+}
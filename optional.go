@@ -0,0 +1,111 @@
+package ya360
+
+import (
+	"encoding/json"
+)
+
+// Optional types are used in update payloads to distinguish three states of
+// the field: a nil pointer leaves the field untouched (the field is omitted
+// in request), `Null` clears the field (`null` is sent) and any other
+// value sets the field to the `Value` (including zero values)
+
+// OptString contains optional string value for update payloads
+type OptString struct {
+	Value string
+	Null  bool
+}
+
+// OptBool contains optional bool value for update payloads
+type OptBool struct {
+	Value bool
+	Null  bool
+}
+
+// OptInt64 contains optional int64 value for update payloads
+type OptInt64 struct {
+	Value int64
+	Null  bool
+}
+
+// SetString returns optional string set to the `v`
+func SetString(v string) *OptString {
+	return &OptString{
+		Value: v,
+	}
+}
+
+// ClearString returns optional string to clear the field
+func ClearString() *OptString {
+	return &OptString{
+		Null: true,
+	}
+}
+
+// SetBool returns optional bool set to the `v`
+func SetBool(v bool) *OptBool {
+	return &OptBool{
+		Value: v,
+	}
+}
+
+// SetInt64 returns optional int64 set to the `v`
+func SetInt64(v int64) *OptInt64 {
+	return &OptInt64{
+		Value: v,
+	}
+}
+
+// ClearInt64 returns optional int64 to clear the field
+func ClearInt64() *OptInt64 {
+	return &OptInt64{
+		Null: true,
+	}
+}
+
+func (o OptString) MarshalJSON() ([]byte, error) {
+	if o.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+func (o *OptString) UnmarshalJSON(data []byte) error {
+	*o = OptString{}
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+func (o OptBool) MarshalJSON() ([]byte, error) {
+	if o.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+func (o *OptBool) UnmarshalJSON(data []byte) error {
+	*o = OptBool{}
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+func (o OptInt64) MarshalJSON() ([]byte, error) {
+	if o.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+func (o *OptInt64) UnmarshalJSON(data []byte) error {
+	*o = OptInt64{}
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
@@ -0,0 +1,43 @@
+package ya360
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOptionalMarshal(t *testing.T) {
+
+	for _, c := range []struct {
+		tx   interface{}
+		json string
+	}{
+		{UserUpdateTx{}, `{}`},
+		{UserUpdateTx{IsEnabled: SetBool(false), IsAdmin: SetBool(false)}, `{"isAdmin":false,"isEnabled":false}`},
+		{UserUpdateTx{Contacts: &[]UserContactTx{}}, `{"contacts":[]}`},
+		{DepartmentUpdateTx{ParentID: SetInt64(0), HeadID: ClearString()}, `{"headId":null,"parentId":0}`},
+		{GroupUpdateTx{Description: SetString(""), Members: &[]MemberIDType{}}, `{"description":"","members":[]}`},
+	} {
+		b, err := json.Marshal(c.tx)
+		if err != nil {
+			t.Fatal("Optional marshal error:", err)
+		}
+		if string(b) != c.json {
+			t.Fatalf("Optional marshal error: incorrect JSON (expected: %s, returned: %s)", c.json, b)
+		}
+	}
+
+	var o struct {
+		A *OptString `json:"a"`
+		B *OptInt64  `json:"b"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"a":null,"b":5}`), &o); err != nil {
+		t.Fatal("Optional unmarshal error:", err)
+	}
+
+	if o.A != nil || o.B == nil || o.B.Value != 5 {
+		t.Fatalf("Optional unmarshal error: incorrect values (returned: %v, %v)", o.A, o.B)
+	}
+
+	t.Logf("Optional marshal: success")
+}
@@ -0,0 +1,98 @@
+package ya360
+
+import (
+	"context"
+	"sync"
+)
+
+// pageFetcher fetches specified page and returns total pages count (zero on error)
+// and a function to pass the fetched page (or error) to the caller
+type pageFetcher func(ctx context.Context, page int64) (int64, func() error)
+
+// pagesWalk fetches all pages and calls `emit` function for each of them.
+// The first page is fetched at first to get the pages count. Other pages are
+// fetched by `workers` goroutines. If `workers` is less than 2 the pages are
+// fetched one by one in order. Emit functions are never called concurrently.
+// Walking stops on the first error returned by an emit function or on context cancel
+func pagesWalk(ctx context.Context, workers int, fetch pageFetcher) error {
+
+	pages, emit := fetch(ctx, 1)
+	if err := emit(); err != nil {
+		return err
+	}
+
+	if workers < 2 {
+		for page := int64(2); page <= pages; page++ {
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			p, emit := fetch(ctx, page)
+			if err := emit(); err != nil {
+				return err
+			}
+
+			// Pages count may be changed during walking
+			if p > 0 {
+				pages = p
+			}
+		}
+		return ctx.Err()
+	}
+
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+
+	jobs := make(chan int64)
+	results := make(chan func() error)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range jobs {
+				_, emit := fetch(wctx, page)
+				select {
+				case results <- emit:
+				case <-wctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for page := int64(2); page <= pages; page++ {
+			select {
+			case jobs <- page:
+			case <-wctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var err error
+	for emit := range results {
+		if err != nil {
+			continue
+		}
+		if err = emit(); err != nil {
+			cancel()
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,129 @@
+package ya360
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit contains settings of the client side rate limiter.
+// The limiter is shared by all copies of the Ya360 value
+// returned by `Init` and safe for concurrent use
+type RateLimit struct {
+
+	// RPS is a number of requests per second allowed to be made.
+	// Zero value disables the limiter
+	RPS float64
+
+	// Burst is a max number of requests allowed to be made at once.
+	// If not set, 1 is used
+	Burst int
+}
+
+// rateLimiter implements token bucket algorithm
+type rateLimiter struct {
+	mu     sync.Mutex
+	rps    float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func rateLimiterInit(r RateLimit) *rateLimiter {
+
+	if r.RPS <= 0 {
+		return nil
+	}
+
+	burst := float64(r.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		rps:    r.RPS,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until request is allowed to be made or context is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+
+	if l == nil {
+		return nil
+	}
+
+	d := l.reserve(1)
+	if d == 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		// Return unused token back into the bucket
+		l.reserve(-1)
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// delay returns time a new request has to wait before it can be made
+func (l *rateLimiter) delay() time.Duration {
+
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance()
+
+	if l.tokens >= 1 {
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rps * float64(time.Second))
+}
+
+// reserve takes `n` tokens from the bucket and
+// returns time to wait until the tokens are available
+func (l *rateLimiter) reserve(n float64) time.Duration {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance()
+
+	l.tokens -= n
+
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rps * float64(time.Second))
+}
+
+// RateLimitWait returns time a new request has to wait
+// in the client side rate limiter before it can be made
+func (ya *Ya360) RateLimitWait() time.Duration {
+	return ya.l.delay()
+}
+
+// advance puts tokens accumulated since the last call into the bucket
+func (l *rateLimiter) advance() {
+
+	now := time.Now()
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rps
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
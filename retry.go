@@ -0,0 +1,115 @@
+package ya360
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	RetryMaxAttemptsDefault = 3
+	RetryMinBackoffDefault  = 500 * time.Millisecond
+	RetryMaxBackoffDefault  = 30 * time.Second
+)
+
+// RetryPolicy contains settings to retry requests failed with
+// `429 Too Many Requests`, 5xx status codes or network errors
+type RetryPolicy struct {
+
+	// MaxAttempts is a total number of attempts for every request
+	// (including the first one). Set it to 1 to disable retries.
+	// If not set, `RetryMaxAttemptsDefault` is used
+	MaxAttempts int
+
+	// MinBackoff is a base delay before the first retry. Every next delay
+	// is doubled up to `MaxBackoff`. A random jitter is added to each delay
+	MinBackoff time.Duration
+
+	// MaxBackoff is an upper limit of delay between attempts,
+	// including delays requested by server with `Retry-After` header
+	MaxBackoff time.Duration
+
+	// RetryNonIdempotent enables retries for POST, PUT, PATCH and DELETE
+	// requests. Only GET requests are retried by default
+	RetryNonIdempotent bool
+}
+
+// attempts returns max attempts count for requests with specified method
+func (p RetryPolicy) attempts(method string) int {
+
+	if method != http.MethodGet && p.RetryNonIdempotent == false {
+		return 1
+	}
+
+	if p.MaxAttempts <= 0 {
+		return RetryMaxAttemptsDefault
+	}
+
+	return p.MaxAttempts
+}
+
+// backoff returns delay before the next attempt.
+// `attempt` is a number of the attempt that has just failed
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+
+	min := p.MinBackoff
+	if min <= 0 {
+		min = RetryMinBackoffDefault
+	}
+
+	max := p.MaxBackoff
+	if max <= 0 {
+		max = RetryMaxBackoffDefault
+	}
+
+	// Server specified delay has priority over the policy,
+	// but it is limited by `MaxBackoff` as well
+	if retryAfter > 0 {
+		if retryAfter > max {
+			return max
+		}
+		return retryAfter
+	}
+
+	d := min
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+
+	// Use a half of delay as a jitter
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryable checks whether request failed with specified status code
+// may be retried. Zero status means a network error
+func retryable(status int) bool {
+	return status == 0 || status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// retryAfter parses `Retry-After` header value,
+// which contains either delay in seconds or HTTP date
+func retryAfter(h string) time.Duration {
+
+	if len(h) == 0 {
+		return 0
+	}
+
+	if s, err := strconv.ParseInt(h, 10, 64); err == nil {
+		if s < 0 {
+			return 0
+		}
+		return time.Duration(s) * time.Second
+	}
+
+	if t, err := http.ParseTime(h); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,182 @@
+package ya360
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServiceApp contains credentials of the service application
+// used to obtain tokens on behalf of organization users
+// Link: https://yandex.ru/dev/api360/doc/concepts/access.html
+type ServiceApp struct {
+	ClientID     string
+	ClientSecret string
+
+	// TokenURL is an OAuth token endpoint.
+	// If not set, `OAuthTokenURLDefault` is used
+	TokenURL string
+}
+
+const (
+	serviceAppGrantType        = "urn:ietf:params:oauth:grant-type:token-exchange"
+	serviceAppSubjectTypeUID   = "urn:yandex:params:oauth:token-type:uid"
+	serviceAppSubjectTypeEmail = "urn:yandex:params:oauth:token-type:email"
+)
+
+// serviceAppTokens contains per-user tokens cache shared
+// by all copies of the Ya360 value returned by `Init`
+type serviceAppTokens struct {
+	app ServiceApp
+	c   *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]serviceAppToken
+
+	// locks serialize token requests per subject, so slow
+	// token requests for one user don't block other users
+	locks map[string]*sync.Mutex
+}
+
+type serviceAppToken struct {
+	token   string
+	expires time.Time
+}
+
+// serviceAppUserToken is a token source for requests on behalf of the user
+type serviceAppUserToken struct {
+	sa      *serviceAppTokens
+	subject string
+}
+
+func serviceAppTokensInit(app ServiceApp, c *http.Client) *serviceAppTokens {
+
+	if len(app.ClientID) == 0 {
+		return nil
+	}
+
+	if len(app.TokenURL) == 0 {
+		app.TokenURL = OAuthTokenURLDefault
+	}
+
+	return &serviceAppTokens{
+		app:    app,
+		c:      c,
+		tokens: make(map[string]serviceAppToken),
+		locks:  make(map[string]*sync.Mutex),
+	}
+}
+
+// ServiceAppToken returns token issued to the service application on behalf of
+// the user with specified ID or email. Tokens are cached until they expire
+func (ya *Ya360) ServiceAppToken(ctx context.Context, subject string) (string, error) {
+	return ya.sa.token(ctx, subject, "")
+}
+
+// AsUser returns Ya360 making requests on behalf of the user with specified ID or email.
+// Tokens for the user are obtained with service application credentials from `Settings.ServiceApp`.
+// Returned value shares HTTP client, rate limiter and tokens cache with the original one
+func (ya *Ya360) AsUser(subject string) Ya360 {
+
+	y := *ya
+	y.t = serviceAppUserToken{
+		sa:      ya.sa,
+		subject: subject,
+	}
+
+	return y
+}
+
+// Token returns cached or newly obtained user token
+func (u serviceAppUserToken) Token(ctx context.Context) (string, error) {
+	return u.sa.token(ctx, u.subject, "")
+}
+
+// RefreshToken obtains new user token if the `token` is still cached
+func (u serviceAppUserToken) RefreshToken(ctx context.Context, token string) (string, error) {
+	return u.sa.token(ctx, u.subject, token)
+}
+
+// token returns token for the subject. If `rejected` token is
+// specified and it is still cached, the new one is obtained
+func (sa *serviceAppTokens) token(ctx context.Context, subject, rejected string) (string, error) {
+
+	if sa == nil {
+		return "", fmt.Errorf("service application token: service application is not configured")
+	}
+
+	l := sa.lock(subject)
+	l.Lock()
+	defer l.Unlock()
+
+	if t, ok := sa.cached(subject, rejected); ok == true {
+		return t, nil
+	}
+
+	st := serviceAppSubjectTypeUID
+	if strings.Contains(subject, "@") {
+		st = serviceAppSubjectTypeEmail
+	}
+
+	v := url.Values{}
+	v.Set("grant_type", serviceAppGrantType)
+	v.Set("client_id", sa.app.ClientID)
+	v.Set("client_secret", sa.app.ClientSecret)
+	v.Set("subject_token", subject)
+	v.Set("subject_token_type", st)
+
+	rx, err := oauthTokenRequest(ctx, sa.c, sa.app.TokenURL, v)
+	if err != nil {
+		return "", fmt.Errorf("service application token for `%s`: %v", subject, err)
+	}
+
+	t := serviceAppToken{
+		token: rx.AccessToken,
+	}
+	if rx.ExpiresIn > 0 {
+		t.expires = time.Now().Add(time.Duration(rx.ExpiresIn) * time.Second)
+	}
+
+	sa.mu.Lock()
+	sa.tokens[subject] = t
+	sa.mu.Unlock()
+
+	return t.token, nil
+}
+
+// lock returns lock for token requests of the subject
+func (sa *serviceAppTokens) lock(subject string) *sync.Mutex {
+
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	l, ok := sa.locks[subject]
+	if ok == false {
+		l = &sync.Mutex{}
+		sa.locks[subject] = l
+	}
+
+	return l
+}
+
+// cached returns cached token for the subject if it is not expired and not rejected
+func (sa *serviceAppTokens) cached(subject, rejected string) (string, bool) {
+
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	t, ok := sa.tokens[subject]
+	if ok == false || t.token == rejected {
+		return "", false
+	}
+
+	if t.expires.IsZero() || time.Now().Add(tokenExpiryDelta).Before(t.expires) {
+		return t.token, true
+	}
+
+	return "", false
+}
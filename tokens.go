@@ -0,0 +1,242 @@
+package ya360
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const OAuthTokenURLDefault = "https://oauth.yandex.ru/token"
+
+// TokenSource provides OAuth token for requests to Yandex 360.
+// Token is requested before every request, so implementations
+// must cache the token and be safe for concurrent use
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenRefresher is an optional interface for TokenSource. If token source
+// implements it, request rejected with `401 Unauthorized` status is retried once
+// after refresh. The rejected `token` is passed to let implementations avoid
+// repeated refreshes in case of concurrent requests
+type TokenRefresher interface {
+	RefreshToken(ctx context.Context, token string) (string, error)
+}
+
+// StaticToken is a token source that always returns the same token
+type StaticToken string
+
+// Token returns the static token
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// FileTokenSource reads token from the file.
+// File is re-read every time it has been changed
+type FileTokenSource struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// FileTokenSourceInit creates token source reading token from the specified file
+func FileTokenSourceInit(path string) *FileTokenSource {
+	return &FileTokenSource{
+		path: path,
+	}
+}
+
+// Token returns token from the file
+func (f *FileTokenSource) Token(ctx context.Context) (string, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.read(false)
+}
+
+// RefreshToken forcibly re-reads token from the file
+func (f *FileTokenSource) RefreshToken(ctx context.Context, token string) (string, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.read(true)
+}
+
+func (f *FileTokenSource) read(force bool) (string, error) {
+
+	fi, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("token file: %v", err)
+	}
+
+	if force == false && len(f.token) > 0 && fi.ModTime().Equal(f.modTime) && fi.Size() == f.size {
+		return f.token, nil
+	}
+
+	b, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("token file: %v", err)
+	}
+
+	t := strings.TrimSpace(string(b))
+	if len(t) == 0 {
+		return "", fmt.Errorf("token file: file `%s` is empty", f.path)
+	}
+
+	f.token = t
+	f.modTime = fi.ModTime()
+	f.size = fi.Size()
+
+	return f.token, nil
+}
+
+// RefreshTokenSettings contains settings for OAuth refresh token flow
+type RefreshTokenSettings struct {
+
+	// TokenURL is an OAuth token endpoint.
+	// If not set, `OAuthTokenURLDefault` is used
+	TokenURL string
+
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+
+	// AccessToken is an optional initial access token
+	AccessToken string
+
+	// HTTPClient is used to make requests to token endpoint.
+	// If not set, `http.DefaultClient` is used
+	HTTPClient *http.Client
+}
+
+// RefreshTokenSource obtains access tokens with OAuth refresh token flow.
+// Token is refreshed when it expires or rejected by Yandex 360
+type RefreshTokenSource struct {
+	s RefreshTokenSettings
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+type oauthTokenRx struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// tokenExpiryDelta is a time before token expiration when token is refreshed
+const tokenExpiryDelta = time.Minute
+
+// RefreshTokenSourceInit creates token source with OAuth refresh token flow
+func RefreshTokenSourceInit(s RefreshTokenSettings) *RefreshTokenSource {
+
+	if len(s.TokenURL) == 0 {
+		s.TokenURL = OAuthTokenURLDefault
+	}
+
+	if s.HTTPClient == nil {
+		s.HTTPClient = http.DefaultClient
+	}
+
+	return &RefreshTokenSource{
+		s:     s,
+		token: s.AccessToken,
+	}
+}
+
+// Token returns access token refreshing it if expired
+func (r *RefreshTokenSource) Token(ctx context.Context) (string, error) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.token) > 0 && (r.expires.IsZero() || time.Now().Add(tokenExpiryDelta).Before(r.expires)) {
+		return r.token, nil
+	}
+
+	return r.refresh(ctx)
+}
+
+// RefreshToken refreshes access token unless it has already been refreshed
+// after the specified `token` was issued
+func (r *RefreshTokenSource) RefreshToken(ctx context.Context, token string) (string, error) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.token) > 0 && r.token != token {
+		return r.token, nil
+	}
+
+	return r.refresh(ctx)
+}
+
+func (r *RefreshTokenSource) refresh(ctx context.Context) (string, error) {
+
+	v := url.Values{}
+	v.Set("grant_type", "refresh_token")
+	v.Set("refresh_token", r.s.RefreshToken)
+	v.Set("client_id", r.s.ClientID)
+	v.Set("client_secret", r.s.ClientSecret)
+
+	t, err := oauthTokenRequest(ctx, r.s.HTTPClient, r.s.TokenURL, v)
+	if err != nil {
+		return "", err
+	}
+
+	r.token = t.AccessToken
+	r.expires = time.Time{}
+	if t.ExpiresIn > 0 {
+		r.expires = time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+
+	// Refresh token may be rotated
+	if len(t.RefreshToken) > 0 {
+		r.s.RefreshToken = t.RefreshToken
+	}
+
+	return r.token, nil
+}
+
+// oauthTokenRequest makes request to OAuth token endpoint
+func oauthTokenRequest(ctx context.Context, c *http.Client, tokenURL string, v url.Values) (oauthTokenRx, error) {
+
+	var t oauthTokenRx
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return t, fmt.Errorf("oauth token: can't create new request: %v", err)
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.Do(req)
+	if err != nil {
+		return t, fmt.Errorf("oauth token: %v", err)
+	}
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(res.Body).Decode(&t); err != nil {
+		return t, fmt.Errorf("oauth token: wrong status code %d: can't decode response body: %v", res.StatusCode, err)
+	}
+
+	if res.StatusCode != http.StatusOK || len(t.AccessToken) == 0 {
+		return t, fmt.Errorf("oauth token: wrong status code %d: %s: %s", res.StatusCode, t.Error, t.ErrorDescription)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,218 @@
+package ya360
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenRefresh(t *testing.T) {
+
+	var refreshes int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&refreshes, 1)
+		if r.FormValue("grant_type") != "refresh_token" || r.FormValue("refresh_token") != "refresh" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant","error_description":"wrong refresh token"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"new","expires_in":3600,"token_type":"bearer"}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "OAuth new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":"1"}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	y := Init(Settings{
+		URL:   srv.URL,
+		OrgID: 1,
+		TokenSource: RefreshTokenSourceInit(RefreshTokenSettings{
+			TokenURL:     srv.URL + "/token",
+			ClientID:     "client",
+			ClientSecret: "secret",
+			RefreshToken: "refresh",
+			AccessToken:  "old",
+		}),
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := y.UserGet(context.Background(), "1"); err != nil {
+			t.Fatal("Token refresh error:", err)
+		}
+	}
+
+	if r := atomic.LoadInt32(&refreshes); r != 1 {
+		t.Fatalf("Token refresh error: incorrect refreshes count (returned: %d)", r)
+	}
+
+	t.Logf("Token refresh: success")
+}
+
+func TestTokenFile(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "token")
+
+	if err := os.WriteFile(path, []byte("first\n"), 0600); err != nil {
+		t.Fatal("Token file error:", err)
+	}
+
+	f := FileTokenSourceInit(path)
+
+	if tk, err := f.Token(context.Background()); err != nil || tk != "first" {
+		t.Fatalf("Token file error: incorrect token (returned: %s, %v)", tk, err)
+	}
+
+	if err := os.WriteFile(path, []byte("second\n"), 0600); err != nil {
+		t.Fatal("Token file error:", err)
+	}
+
+	if tk, err := f.Token(context.Background()); err != nil || tk != "second" {
+		t.Fatalf("Token file error: token must be re-read (returned: %s, %v)", tk, err)
+	}
+
+	t.Logf("Token file: success")
+}
+
+func TestServiceAppToken(t *testing.T) {
+
+	var exchanges int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&exchanges, 1)
+		st := "uid"
+		if r.FormValue("subject_token_type") == serviceAppSubjectTypeEmail {
+			st = "email"
+		}
+		w.Write([]byte(`{"access_token":"` + st + `-` + r.FormValue("subject_token") + `","expires_in":3600}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"` + r.Header.Get("Authorization") + `"}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	y := Init(Settings{
+		URL:   srv.URL,
+		OAuth: "admin",
+		OrgID: 1,
+		ServiceApp: ServiceApp{
+			ClientID:     "client",
+			ClientSecret: "secret",
+			TokenURL:     srv.URL + "/token",
+		},
+	})
+
+	for _, c := range []struct {
+		subject string
+		auth    string
+	}{
+		{"42", "OAuth uid-42"},
+		{"42", "OAuth uid-42"},
+		{"user@example.com", "OAuth email-user@example.com"},
+	} {
+		y := y.AsUser(c.subject)
+		u, err := y.UserGet(context.Background(), "1")
+		if err != nil {
+			t.Fatal("Service app token error:", err)
+		}
+		if u.ID != c.auth {
+			t.Fatalf("Service app token error: incorrect authorization (returned: %s)", u.ID)
+		}
+	}
+
+	if u, err := y.UserGet(context.Background(), "1"); err != nil || u.ID != "OAuth admin" {
+		t.Fatalf("Service app token error: original token must be used (returned: %s, %v)", u.ID, err)
+	}
+
+	if e := atomic.LoadInt32(&exchanges); e != 2 {
+		t.Fatalf("Service app token error: incorrect exchanges count (returned: %d)", e)
+	}
+
+	t.Logf("Service app token: success")
+}
+
+func TestServiceAppTokenConcurrent(t *testing.T) {
+
+	var exchanges int32
+
+	slow := make(chan struct{})
+	started := make(chan struct{}, 2)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&exchanges, 1)
+		if r.FormValue("subject_token") == "slow" {
+			started <- struct{}{}
+			<-slow
+		}
+		w.Write([]byte(`{"access_token":"` + r.FormValue("subject_token") + `","expires_in":3600}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"` + r.Header.Get("Authorization") + `"}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	y := Init(Settings{
+		URL:   srv.URL,
+		OAuth: "admin",
+		OrgID: 1,
+		ServiceApp: ServiceApp{
+			ClientID:     "client",
+			ClientSecret: "secret",
+			TokenURL:     srv.URL + "/token",
+		},
+	})
+
+	done := make(chan error)
+	for i := 0; i < 2; i++ {
+		go func() {
+			y := y.AsUser("slow")
+			_, err := y.UserGet(context.Background(), "1")
+			done <- err
+		}()
+	}
+
+	<-started
+
+	// Token request for other user must not wait for the slow one
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	f := y.AsUser("fast")
+	u, err := f.UserGet(ctx, "1")
+	if err != nil || u.ID != "OAuth fast" {
+		t.Fatalf("Service app token concurrent error: other user is blocked (returned: %s, %v)", u.ID, err)
+	}
+
+	close(slow)
+
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil {
+			t.Fatal("Service app token concurrent error:", err)
+		}
+	}
+
+	// Concurrent requests for the same user share one token exchange
+	if e := atomic.LoadInt32(&exchanges); e != 2 {
+		t.Fatalf("Service app token concurrent error: incorrect exchanges count (returned: %d)", e)
+	}
+
+	t.Logf("Service app token concurrent: success")
+}
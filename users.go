@@ -1,10 +1,17 @@
 package ya360
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"net/mail"
 	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 // UsersRx contains users list
@@ -70,6 +77,42 @@ type UserAliasDeleteRx struct {
 	Removed bool   `json:"removed"`
 }
 
+// User2FARx contains user two-factor authentication status
+type User2FARx struct {
+	UserID           string `json:"userId"`
+	Has2FA           bool   `json:"has2fa"`
+	HasSecurityPhone bool   `json:"hasSecurityPhone"`
+}
+
+// Users2FAListRx contains two-factor authentication statuses of all users
+// and the users whose statuses failed to get
+type Users2FAListRx struct {
+	Users  []User2FARx
+	Failed []Users2FAListFailure
+}
+
+// Users2FAListFailure contains user whose two-factor authentication status failed to get
+type Users2FAListFailure struct {
+	UserID string
+	Err    error
+}
+
+// User2FADeleteRx contains result of user two-factor authentication reset operation
+type User2FADeleteRx struct {
+	UserID string `json:"userId"`
+}
+
+// UserAvatarRx contains result of user avatar upload operation
+type UserAvatarRx struct {
+	AvatarID string `json:"avatarId"`
+}
+
+// UserAvatarDeleteRx contains result of user avatar delete operation
+type UserAvatarDeleteRx struct {
+	UserID  string `json:"userId"`
+	Removed bool   `json:"removed"`
+}
+
 // UserCreateTx contains data to create new user
 type UserCreateTx struct {
 	About        string          `json:"about,omitempty"`
@@ -87,23 +130,24 @@ type UserCreateTx struct {
 	Timezone     string          `json:"timezone,omitempty"`
 }
 
-// UserUpdateTx contains data to update user
+// UserUpdateTx contains data to update user.
+// Nil fields are not changed, see `OptString` for details
 type UserUpdateTx struct {
-	About                  string          `json:"about,omitempty"`
-	Birthday               string          `json:"birthday,omitempty"`
-	Contacts               []UserContactTx `json:"contacts,omitempty"`
-	DepartmentID           int64           `json:"departmentId,omitempty"`
-	ExternalID             string          `json:"externalId,omitempty"`
-	Gender                 string          `json:"gender,omitempty"`
-	IsAdmin                bool            `json:"isAdmin,omitempty"`
-	IsEnabled              bool            `json:"isEnabled,omitempty"`
-	Language               string          `json:"language,omitempty"`
-	Name                   UserName        `json:"name,omitempty"`
-	Nickname               string          `json:"nickname,omitempty"`
-	Password               string          `json:"password,omitempty"`
-	PasswordChangeRequired bool            `json:"passwordChangeRequired,omitempty"`
-	Position               string          `json:"position,omitempty"`
-	Timezone               string          `json:"timezone,omitempty"`
+	About                  *OptString       `json:"about,omitempty"`
+	Birthday               *OptString       `json:"birthday,omitempty"`
+	Contacts               *[]UserContactTx `json:"contacts,omitempty"`
+	DepartmentID           *OptInt64        `json:"departmentId,omitempty"`
+	ExternalID             *OptString       `json:"externalId,omitempty"`
+	Gender                 *OptString       `json:"gender,omitempty"`
+	IsAdmin                *OptBool         `json:"isAdmin,omitempty"`
+	IsEnabled              *OptBool         `json:"isEnabled,omitempty"`
+	Language               *OptString       `json:"language,omitempty"`
+	Name                   *UserName        `json:"name,omitempty"`
+	Nickname               *OptString       `json:"nickname,omitempty"`
+	Password               *OptString       `json:"password,omitempty"`
+	PasswordChangeRequired *OptBool         `json:"passwordChangeRequired,omitempty"`
+	Position               *OptString       `json:"position,omitempty"`
+	Timezone               *OptString       `json:"timezone,omitempty"`
 }
 
 // UserContactTx contains user contacts for transmit operations
@@ -112,11 +156,50 @@ type UserContactTx struct {
 	Value string          `json:"value"`
 }
 
+// UserContactsUpdateTx contains data to replace user contacts
+type UserContactsUpdateTx struct {
+	Contacts []UserContactTx `json:"contacts"`
+}
+
 // UserAliasAddTx contains data to add new alias for user
 type UserAliasAddTx struct {
 	Alias string `json:"alias"`
 }
 
+// UserOffboardTx contains settings of user offboarding
+type UserOffboardTx struct {
+
+	// HeadSuccessorID is an ID of user to hand over the department head
+	// role to. If not set, heads of departments are not changed
+	HeadSuccessorID string
+}
+
+// UserOffboardRx contains audit record of user offboarding
+type UserOffboardRx struct {
+	UserID string
+	Steps  []UserOffboardStep
+}
+
+// UserOffboardStep contains a single offboarding step.
+// Target is an ID of the group or department the step is applied to
+type UserOffboardStep struct {
+	Action UserOffboardAction
+	Target string
+	Err    error
+}
+
+type UserOffboardAction string
+
+const (
+	UserOffboardActionBlock        UserOffboardAction = "block"
+	UserOffboardActionGroupLeave   UserOffboardAction = "group_leave"
+	UserOffboardActionHeadHandover UserOffboardAction = "head_handover"
+)
+
+func (a UserOffboardAction) String() string {
+	return string(a)
+}
+
 type UserContactType string
 
 const (
@@ -133,9 +216,83 @@ func (t UserContactType) String() string {
 	return string(t)
 }
 
+var (
+	userContactPhoneRegexp      = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{3,28}[0-9]$`)
+	userContactPhoneExtRegexp   = regexp.MustCompile(`^[0-9]{1,10}$`)
+	userContactICQRegexp        = regexp.MustCompile(`^[0-9]{5,10}$`)
+	userContactTwitterRegexp    = regexp.MustCompile(`^@?[A-Za-z0-9_]{1,15}$`)
+	userContactSkypeRegexp      = regexp.MustCompile(`^(live:)?[A-Za-z][A-Za-z0-9.,_-]{5,31}$`)
+	userContactEmailLocalRegexp = regexp.MustCompile(`^[A-Za-z0-9._%+-]+$`)
+)
+
+// Validate checks contact value format in accordance with contact type
+func (c UserContactTx) Validate() error {
+
+	if len(c.Value) == 0 {
+		return fmt.Errorf("contact `%s`: empty value", c.Type)
+	}
+
+	valid := false
+
+	switch c.Type {
+	case UserContactTypeEmail:
+		a, err := mail.ParseAddress(c.Value)
+		if err == nil && a.Address == c.Value {
+			i := strings.LastIndex(c.Value, "@")
+			valid = userContactEmailLocalRegexp.MatchString(c.Value[:i]) && strings.Contains(c.Value[i+1:], ".")
+		}
+	case UserContactTypePhone:
+		valid = userContactPhoneRegexp.MatchString(c.Value)
+	case UserContactTypePhoneExtension:
+		valid = userContactPhoneExtRegexp.MatchString(c.Value)
+	case UserContactTypeSite:
+		// Scheme is optional, plain domains are allowed
+		v := c.Value
+		if strings.Contains(v, "://") == false {
+			v = "http://" + v
+		}
+		u, err := url.Parse(v)
+		valid = err == nil && (u.Scheme == "http" || u.Scheme == "https") && len(u.Host) > 0 && strings.ContainsAny(c.Value, " \t") == false
+	case UserContactTypeICQ:
+		valid = userContactICQRegexp.MatchString(c.Value)
+	case UserContactTypeTwitter:
+		valid = userContactTwitterRegexp.MatchString(c.Value)
+	case UserContactTypeSkype:
+		valid = userContactSkypeRegexp.MatchString(c.Value)
+	default:
+		return fmt.Errorf("contact `%s`: unknown contact type", c.Type)
+	}
+
+	if valid == false {
+		return fmt.Errorf("contact `%s`: wrong value format `%s`", c.Type, c.Value)
+	}
+
+	return nil
+}
+
+// UserContactsEditable returns user contacts that can be changed with
+// `UserContactsUpdate`. Synthetic contacts (e.g. main email and aliases)
+// are managed by Yandex 360 and kept as is on contacts update
+func UserContactsEditable(contacts []UserContactRx) []UserContactTx {
+
+	c := []UserContactTx{}
+
+	for _, e := range contacts {
+		if e.Synthetic || e.Alias {
+			continue
+		}
+		c = append(c, UserContactTx{
+			Type:  e.Type,
+			Value: e.Value,
+		})
+	}
+
+	return c
+}
+
 // UserCreate creates new user
 // Link: https://yandex.ru/dev/api360/doc/ref/UserService/UserService_Create.html
-func (ya *Ya360) UserCreate(user UserCreateTx) (UserRx, error) {
+func (ya *Ya360) UserCreate(ctx context.Context, user UserCreateTx) (UserRx, error) {
 
 	var (
 		resp UserRx
@@ -148,12 +305,8 @@ func (ya *Ya360) UserCreate(user UserCreateTx) (UserRx, error) {
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodPost, ur, user, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.alter(ctx, http.MethodPost, ur, user, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
@@ -161,7 +314,7 @@ func (ya *Ya360) UserCreate(user UserCreateTx) (UserRx, error) {
 
 // UserGet gets specified user
 // Link: https://yandex.ru/dev/api360/doc/ref/UserService/UserService_Get.html
-func (ya *Ya360) UserGet(userID string) (UserRx, error) {
+func (ya *Ya360) UserGet(ctx context.Context, userID string) (UserRx, error) {
 
 	var (
 		resp UserRx
@@ -174,20 +327,131 @@ func (ya *Ya360) UserGet(userID string) (UserRx, error) {
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.get(ur, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.get(ctx, ur, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
 }
 
+// UserGetByEmail gets user by email. Both the main email and emails
+// of user aliases are matched. Lookup uses cached users list (see `Settings.UsersCacheTTL`)
+func (ya *Ya360) UserGetByEmail(ctx context.Context, email string) (UserRx, error) {
+	return ya.userFind(ctx, "email", email, func(u UserRx) bool {
+		if strings.EqualFold(u.Email, email) {
+			return true
+		}
+		for _, c := range u.Contacts {
+			if c.Type == UserContactTypeEmail && strings.EqualFold(c.Value, email) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// UserGetByNickname gets user by nickname.
+// Lookup uses cached users list (see `Settings.UsersCacheTTL`)
+func (ya *Ya360) UserGetByNickname(ctx context.Context, nickname string) (UserRx, error) {
+	return ya.userFind(ctx, "nickname", nickname, func(u UserRx) bool {
+		return strings.EqualFold(u.Nickname, nickname)
+	})
+}
+
+// UserGetByExternalID gets user by external ID using the users list filter.
+// If the filter is not applied by server, lookup falls back to the cached
+// users list (see `Settings.UsersCacheTTL`)
+func (ya *Ya360) UserGetByExternalID(ctx context.Context, externalID string) (UserRx, error) {
+
+	match := func(u UserRx) bool {
+		return len(u.ExternalID) > 0 && u.ExternalID == externalID
+	}
+
+	if len(externalID) > 0 {
+
+		l, err := ya.UsersList(ctx, 1, 1, ListOptions{
+			ExternalID: externalID,
+		})
+		if err != nil {
+			return UserRx{}, err
+		}
+
+		// Empty list means there is no such user
+		if len(l.Users) == 0 {
+			return UserRx{}, UserNotFoundError{
+				Field: "externalId",
+				Value: externalID,
+			}
+		}
+
+		if match(l.Users[0]) {
+			return l.Users[0], nil
+		}
+	}
+
+	return ya.userFind(ctx, "externalId", externalID, match)
+}
+
+// UserFindByAlias finds user by alias. Alias may be specified either as a login
+// or as an email. Lookup uses cached users list (see `Settings.UsersCacheTTL`)
+func (ya *Ya360) UserFindByAlias(ctx context.Context, alias string) (UserRx, error) {
+
+	a := alias
+	if i := strings.LastIndex(a, "@"); i >= 0 {
+		a = a[:i]
+	}
+
+	return ya.userFind(ctx, "alias", alias, func(u UserRx) bool {
+		for _, e := range u.Aliases {
+			if strings.EqualFold(e, a) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// userFind looks for the first user matching `match` within the cached users list.
+// If user is not found within the previously cached list, the list is reloaded once
+func (ya *Ya360) userFind(ctx context.Context, field, value string, match func(u UserRx) bool) (UserRx, error) {
+
+	load := func(ctx context.Context) ([]UserRx, error) {
+		return ya.UsersAll(ctx, usersCachePerPage, ListOptions{}, 1)
+	}
+
+	// On a miss the cache is reloaded once, unless it has
+	// been just loaded or is reloaded by someone else
+	var stale time.Time
+
+	for i := 0; i < 2 && len(value) > 0; i++ {
+
+		users, updated, loaded, err := ya.uc.get(ctx, stale, load)
+		if err != nil {
+			return UserRx{}, err
+		}
+
+		for _, u := range users {
+			if match(u) {
+				return u, nil
+			}
+		}
+
+		if loaded == true {
+			break
+		}
+
+		stale = updated
+	}
+
+	return UserRx{}, UserNotFoundError{
+		Field: field,
+		Value: value,
+	}
+}
+
 // UsersList gets users list
 // Link: https://yandex.ru/dev/api360/doc/ref/UserService/UserService_List.html
-func (ya *Ya360) UsersList(page, perPage int64) (UsersRx, error) {
+func (ya *Ya360) UsersList(ctx context.Context, page, perPage int64, opts ListOptions) (UsersRx, error) {
 
 	var (
 		resp UsersRx
@@ -197,26 +461,137 @@ func (ya *Ya360) UsersList(page, perPage int64) (UsersRx, error) {
 
 	urlParams.Add("page", strconv.FormatInt(page, 10))
 	urlParams.Add("perPage", strconv.FormatInt(perPage, 10))
+	opts.urlParams(urlParams)
 
 	ur := url.URL{
 		Path:     fmt.Sprintf("/directory/v1/org/%d/users", ya.s.OrgID),
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.get(ur, &resp)
+	if err := ya.get(ctx, ur, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// UsersPages walks through all users list pages and calls `fn` for each of them.
+// Page fetch errors are passed into `fn`. Walking stops if `fn` returns an error
+// or context is done. If `workers` is greater than 1 pages after the first one are
+// fetched concurrently and `fn` may be called out of the pages order (but never concurrently)
+func (ya *Ya360) UsersPages(ctx context.Context, perPage int64, opts ListOptions, workers int, fn func(page int64, users UsersRx, err error) error) error {
+	return pagesWalk(ctx, workers, func(ctx context.Context, page int64) (int64, func() error) {
+		l, err := ya.UsersList(ctx, page, perPage, opts)
+		return l.Pages, func() error {
+			return fn(page, l, err)
+		}
+	})
+}
+
+// UsersAll gets all users from all pages of users list
+func (ya *Ya360) UsersAll(ctx context.Context, perPage int64, opts ListOptions, workers int) ([]UserRx, error) {
+
+	pages := make(map[int64][]UserRx)
+
+	err := ya.UsersPages(ctx, perPage, opts, workers, func(page int64, users UsersRx, err error) error {
+		if err != nil {
+			return err
+		}
+		pages[page] = users.Users
+		return nil
+	})
 	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
+		return nil, err
+	}
+
+	users := []UserRx{}
+	for page := int64(1); page <= int64(len(pages)); page++ {
+		users = append(users, pages[page]...)
+	}
+
+	return users, nil
+}
+
+// UsersIterator iterates over users lazily fetching users list pages one by one
+type UsersIterator struct {
+	ya      *Ya360
+	ctx     context.Context
+	perPage int64
+	opts    ListOptions
+
+	page  int64
+	pages int64
+	users []UserRx
+	i     int
+	user  UserRx
+	err   error
+}
+
+// UsersIter creates iterator over all users
+func (ya *Ya360) UsersIter(ctx context.Context, perPage int64, opts ListOptions) *UsersIterator {
+	return &UsersIterator{
+		ya:      ya,
+		ctx:     ctx,
+		perPage: perPage,
+		opts:    opts,
+	}
+}
+
+// Next advances iterator to the next user. It returns false when
+// all users have been iterated or an error occurred
+func (it *UsersIterator) Next() bool {
+
+	for it.i >= len(it.users) {
+
+		if it.err != nil || (it.page > 0 && it.page >= it.pages) {
+			return false
+		}
+
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		l, err := it.ya.UsersList(it.ctx, it.page+1, it.perPage, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page++
+		it.pages = l.Pages
+		it.users = l.Users
+		it.i = 0
+
+		if len(it.users) == 0 {
+			return false
 		}
 	}
 
-	return resp, nil
+	it.user = it.users[it.i]
+	it.i++
+
+	return true
+}
+
+// User returns current user
+func (it *UsersIterator) User() UserRx {
+	return it.user
+}
+
+// Page returns number of the last fetched page
+func (it *UsersIterator) Page() int64 {
+	return it.page
+}
+
+// Err returns error occurred during iteration
+func (it *UsersIterator) Err() error {
+	return it.err
 }
 
 // UserUpdate updates specified user with new `user` data
 // Link: https://yandex.ru/dev/api360/doc/ref/UserService/UserService_Update.html
-func (ya *Ya360) UserUpdate(userID string, user UserUpdateTx) (UserRx, error) {
+func (ya *Ya360) UserUpdate(ctx context.Context, userID string, user UserUpdateTx) (UserRx, error) {
 
 	var (
 		resp UserRx
@@ -229,12 +604,8 @@ func (ya *Ya360) UserUpdate(userID string, user UserUpdateTx) (UserRx, error) {
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodPatch, ur, user, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.alter(ctx, http.MethodPatch, ur, user, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
@@ -242,7 +613,7 @@ func (ya *Ya360) UserUpdate(userID string, user UserUpdateTx) (UserRx, error) {
 
 // UserAliasAdd adds new alias to specified user
 // Link: https://yandex.ru/dev/api360/doc/ref/UserService/UserService_CreateUserAlias.html
-func (ya *Ya360) UserAliasAdd(userID string, alias UserAliasAddTx) (UserRx, error) {
+func (ya *Ya360) UserAliasAdd(ctx context.Context, userID string, alias UserAliasAddTx) (UserRx, error) {
 
 	var (
 		resp UserRx
@@ -255,12 +626,8 @@ func (ya *Ya360) UserAliasAdd(userID string, alias UserAliasAddTx) (UserRx, erro
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodPost, ur, alias, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
-		}
+	if err := ya.alter(ctx, http.MethodPost, ur, alias, &resp); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
@@ -268,7 +635,7 @@ func (ya *Ya360) UserAliasAdd(userID string, alias UserAliasAddTx) (UserRx, erro
 
 // UserAliasDelete deletes alias from specified user
 // Link: https://yandex.ru/dev/api360/doc/ref/UserService/UserService_DeleteUserAlias.html
-func (ya *Ya360) UserAliasDelete(userID, alias string) (UserAliasDeleteRx, error) {
+func (ya *Ya360) UserAliasDelete(ctx context.Context, userID, alias string) (UserAliasDeleteRx, error) {
 
 	var (
 		resp UserAliasDeleteRx
@@ -281,20 +648,291 @@ func (ya *Ya360) UserAliasDelete(userID, alias string) (UserAliasDeleteRx, error
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodDelete, ur, nil, &resp)
-	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
+	if err := ya.alter(ctx, http.MethodDelete, ur, nil, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// User2FAGet gets two-factor authentication status for specified user
+// Link: https://yandex.ru/dev/api360/doc/ref/UserService/UserService_Get2fa.html
+func (ya *Ya360) User2FAGet(ctx context.Context, userID string) (User2FARx, error) {
+
+	var (
+		resp User2FARx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/directory/v1/org/%d/users/%s/2fa", ya.s.OrgID, userID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.get(ctx, ur, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// User2FADelete resets two-factor authentication for specified user
+// (deletes the configured security phone)
+// Link: https://yandex.ru/dev/api360/doc/ref/UserService/UserService_Delete2fa.html
+func (ya *Ya360) User2FADelete(ctx context.Context, userID string) (User2FADeleteRx, error) {
+
+	var (
+		resp User2FADeleteRx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/directory/v1/org/%d/users/%s/2fa", ya.s.OrgID, userID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.alter(ctx, http.MethodDelete, ur, nil, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// Users2FAList gets two-factor authentication status for all users.
+// Users are iterated page by page and statuses are requested
+// using up to `workers` concurrent requests. Failed requests don't
+// stop the scan, such users are listed in the `Failed` of the result
+// and an error is returned along with the statuses got successfully
+func (ya *Ya360) Users2FAList(ctx context.Context, perPage int64, workers int) (Users2FAListRx, error) {
+
+	var (
+		resp  Users2FAListRx
+		users []User2FARx
+		errs  []error
+		ids   []string
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+	)
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	sem := make(chan struct{}, workers)
+
+	it := ya.UsersIter(ctx, perPage, ListOptions{})
+	for it.Next() {
+
+		mu.Lock()
+		i := len(users)
+		users = append(users, User2FARx{})
+		errs = append(errs, nil)
+		ids = append(ids, it.User().ID)
+		mu.Unlock()
+
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func(userID string) {
+
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			r, err := ya.User2FAGet(ctx, userID)
+
+			mu.Lock()
+			users[i] = r
+			errs[i] = err
+			mu.Unlock()
+		}(it.User().ID)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			resp.Failed = append(resp.Failed, Users2FAListFailure{
+				UserID: ids[i],
+				Err:    err,
+			})
+			continue
 		}
+		resp.Users = append(resp.Users, users[i])
+	}
+
+	if err := it.Err(); err != nil {
+		return resp, err
+	}
+
+	if len(resp.Failed) > 0 {
+		return resp, fmt.Errorf("users 2fa list: %d of %d users failed, first error: %w", len(resp.Failed), len(ids), resp.Failed[0].Err)
 	}
 
 	return resp, nil
 }
 
+// UserAvatarUpload uploads new avatar image for specified user.
+// `contentType` is a MIME type of the image, e.g. `image/jpeg` or `image/png`
+// Link: https://yandex.ru/dev/api360/doc/ref/UserService/UserService_UploadAvatar.html
+func (ya *Ya360) UserAvatarUpload(ctx context.Context, userID string, image io.Reader, contentType string) (UserAvatarRx, error) {
+
+	var (
+		resp UserAvatarRx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/directory/v1/org/%d/users/%s/avatar", ya.s.OrgID, userID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.upload(ctx, http.MethodPut, ur, image, contentType, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// UserAvatarDelete deletes avatar of specified user
+// Link: https://yandex.ru/dev/api360/doc/ref/UserService/UserService_DeleteAvatar.html
+func (ya *Ya360) UserAvatarDelete(ctx context.Context, userID string) (UserAvatarDeleteRx, error) {
+
+	var (
+		resp UserAvatarDeleteRx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/directory/v1/org/%d/users/%s/avatar", ya.s.OrgID, userID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.alter(ctx, http.MethodDelete, ur, nil, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// UserContactsUpdate replaces contacts of specified user with new `contacts`.
+// Synthetic contacts are kept by Yandex 360. Contacts are validated before request
+// Link: https://yandex.ru/dev/api360/doc/ref/UserService/UserService_UpdateContacts.html
+func (ya *Ya360) UserContactsUpdate(ctx context.Context, userID string, contacts []UserContactTx) (UserRx, error) {
+
+	var (
+		resp UserRx
+	)
+
+	for _, c := range contacts {
+		if err := c.Validate(); err != nil {
+			return resp, err
+		}
+	}
+
+	return ya.userContactsPut(ctx, userID, contacts)
+}
+
+// userContactsPut replaces contacts of specified user without validation
+func (ya *Ya360) userContactsPut(ctx context.Context, userID string, contacts []UserContactTx) (UserRx, error) {
+
+	var (
+		resp UserRx
+	)
+
+	if contacts == nil {
+		contacts = []UserContactTx{}
+	}
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/directory/v1/org/%d/users/%s/contacts", ya.s.OrgID, userID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.alter(ctx, http.MethodPut, ur, UserContactsUpdateTx{Contacts: contacts}, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// UserContactsDelete deletes all contacts of specified user except synthetic ones
+// Link: https://yandex.ru/dev/api360/doc/ref/UserService/UserService_DeleteContacts.html
+func (ya *Ya360) UserContactsDelete(ctx context.Context, userID string) (UserRx, error) {
+
+	var (
+		resp UserRx
+	)
+
+	urlParams := url.Values{}
+
+	ur := url.URL{
+		Path:     fmt.Sprintf("/directory/v1/org/%d/users/%s/contacts", ya.s.OrgID, userID),
+		RawQuery: urlParams.Encode(),
+	}
+
+	if err := ya.alter(ctx, http.MethodDelete, ur, nil, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// UserContactAdd adds new contact to specified user keeping other contacts.
+// Only the new contact is validated, existing contacts are sent back as is
+func (ya *Ya360) UserContactAdd(ctx context.Context, userID string, contact UserContactTx) (UserRx, error) {
+
+	if err := contact.Validate(); err != nil {
+		return UserRx{}, err
+	}
+
+	u, err := ya.UserGet(ctx, userID)
+	if err != nil {
+		return u, err
+	}
+
+	c := UserContactsEditable(u.Contacts)
+	for _, e := range c {
+		if e == contact {
+			return u, nil
+		}
+	}
+
+	return ya.userContactsPut(ctx, userID, append(c, contact))
+}
+
+// UserContactRemove removes contact from specified user keeping other contacts
+func (ya *Ya360) UserContactRemove(ctx context.Context, userID string, contact UserContactTx) (UserRx, error) {
+
+	u, err := ya.UserGet(ctx, userID)
+	if err != nil {
+		return u, err
+	}
+
+	ec := UserContactsEditable(u.Contacts)
+
+	c := []UserContactTx{}
+	for _, e := range ec {
+		if e != contact {
+			c = append(c, e)
+		}
+	}
+
+	if len(c) == len(ec) {
+		return u, nil
+	}
+
+	return ya.userContactsPut(ctx, userID, c)
+}
+
 // UserDelete deletes user
 // Link: not implemented yet in Yandex 360
-func (ya *Ya360) UserDelete(userID string) (UserDeleteRx, error) {
+func (ya *Ya360) UserDelete(ctx context.Context, userID string) (UserDeleteRx, error) {
 
 	var (
 		resp UserDeleteRx
@@ -307,13 +945,79 @@ func (ya *Ya360) UserDelete(userID string) (UserDeleteRx, error) {
 		RawQuery: urlParams.Encode(),
 	}
 
-	status, err := ya.alter(http.MethodDelete, ur, nil, &resp)
+	if err := ya.alter(ctx, http.MethodDelete, ur, nil, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// UserBlock disables specified user
+func (ya *Ya360) UserBlock(ctx context.Context, userID string) (UserRx, error) {
+	return ya.UserUpdate(ctx, userID, UserUpdateTx{
+		IsEnabled: SetBool(false),
+	})
+}
+
+// UserUnblock enables specified user
+func (ya *Ya360) UserUnblock(ctx context.Context, userID string) (UserRx, error) {
+	return ya.UserUpdate(ctx, userID, UserUpdateTx{
+		IsEnabled: SetBool(true),
+	})
+}
+
+// UserOffboard disables specified user, removes it from all groups it is a direct
+// member of and optionally hands over the head role for all departments headed by user.
+// Offboarding is interrupted only if user can't be disabled, other failed steps are
+// recorded into the audit record and the first error is returned after all steps
+func (ya *Ya360) UserOffboard(ctx context.Context, userID string, settings UserOffboardTx) (UserOffboardRx, error) {
+
+	var firstErr error
+
+	r := UserOffboardRx{
+		UserID: userID,
+	}
+
+	step := func(a UserOffboardAction, target string, err error) {
+		r.Steps = append(r.Steps, UserOffboardStep{
+			Action: a,
+			Target: target,
+			Err:    err,
+		})
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	u, err := ya.UserBlock(ctx, userID)
+	step(UserOffboardActionBlock, userID, err)
 	if err != nil {
-		return resp, Error{
-			Code: status,
-			Text: err.Error(),
+		return r, err
+	}
+
+	for _, g := range u.Groups {
+		_, err := ya.GroupMemberDelete(ctx, g, MemberTypeUser, userID)
+		step(UserOffboardActionGroupLeave, strconv.FormatInt(g, 10), err)
+	}
+
+	if len(settings.HeadSuccessorID) > 0 {
+
+		deps, err := ya.DepartmentsAll(ctx, departmentsTreePerPage, ListOptions{}, 1)
+		if err != nil {
+			step(UserOffboardActionHeadHandover, "", fmt.Errorf("can't get departments: %w", err))
+			return r, firstErr
+		}
+
+		for _, d := range deps {
+			if d.HeadID != userID {
+				continue
+			}
+			_, err := ya.DepartmentUpdate(ctx, d.ID, DepartmentUpdateTx{
+				HeadID: SetString(settings.HeadSuccessorID),
+			})
+			step(UserOffboardActionHeadHandover, strconv.FormatInt(d.ID, 10), err)
 		}
 	}
 
-	return resp, nil
+	return r, firstErr
 }
@@ -1,9 +1,11 @@
 package ya360
 
 import (
-	"os"
-	"strconv"
+	"bytes"
+	"context"
+	"errors"
 	"testing"
+	"time"
 )
 
 var (
@@ -15,39 +17,162 @@ var (
 	testUserUpdatedFirstName = "TestUserUpdatedFirstName"
 	testUserUpdatedLastName  = "TestUserUpdatedLastName"
 
-	testUserAlias = "testusernickamealias"
-)
+	testUserAlias      = "testusernickamealias"
+	testUserExternalID = "testuserexternalid"
 
-func TestUsersCRUD(t *testing.T) {
+	testUserSuccessorNickname = "testusersuccessor"
 
-	oAuth := os.Getenv("YA360_OAUTH")
-	orgID, err := strconv.ParseInt(os.Getenv("YA360_ORG_ID"), 10, 64)
-	if err != nil {
-		t.Fatal("Init error: make sure environment variable `YA360_ORG_ID` correctly defined:", err)
+	testUserContactPhone = UserContactTx{
+		Type:  UserContactTypePhone,
+		Value: "+79991234567",
 	}
-	if len(oAuth) == 0 {
-		t.Fatal("Init error: make sure environment variable `YA360_OAUTH` correctly defined")
+	testUserContactSite = UserContactTx{
+		Type:  UserContactTypeSite,
+		Value: "https://example.com",
 	}
 
-	y := Init(Settings{
-		OAuth: oAuth,
-		OrgID: orgID,
-	})
+	// 1x1 transparent PNG image
+	testUserAvatar = []byte{
+		0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d, 0x49, 0x48, 0x44, 0x52,
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x08, 0x06, 0x00, 0x00, 0x00, 0x1f, 0x15, 0xc4,
+		0x89, 0x00, 0x00, 0x00, 0x0d, 0x49, 0x44, 0x41, 0x54, 0x78, 0x9c, 0x63, 0x00, 0x01, 0x00, 0x00,
+		0x05, 0x00, 0x01, 0x0d, 0x0a, 0x2d, 0xb4, 0x00, 0x00, 0x00, 0x00, 0x49, 0x45, 0x4e, 0x44, 0xae,
+		0x42, 0x60, 0x82,
+	}
+)
+
+func TestUsersCRUD(t *testing.T) {
+
+	y := testInit(t)
 
 	uCreated := testUserCreate(t, y)
 	defer testUserDetele(t, y, uCreated.ID)
 
 	testUserGet(t, y, uCreated.ID)
 	testUsersList(t, y)
+	testUsersListOptions(t, y, uCreated.ID)
 
 	testUserUpdate(t, y, uCreated.ID)
+	testUserBlock(t, y, uCreated.ID)
+	testUserUnblock(t, y, uCreated.ID)
 	testUserAliasAdd(t, y, uCreated.ID)
+	testUserLookup(t, y, uCreated)
 	testUserAliasDelete(t, y, uCreated.ID)
+
+	testUser2FAGet(t, y, uCreated.ID)
+	testUsers2FAList(t, y, uCreated.ID)
+	testUser2FADelete(t, y, uCreated.ID)
+
+	testUserAvatarUpload(t, y, uCreated.ID)
+	testUserAvatarDelete(t, y, uCreated.ID)
+
+	testUserContactAdd(t, y, uCreated.ID)
+	testUserContactRemove(t, y, uCreated.ID)
+	testUserContactsUpdate(t, y, uCreated.ID)
+	testUserContactsDelete(t, y, uCreated.ID)
+	testUserContactsKeepExisting(t, y, uCreated.ID)
+}
+
+func TestUserOffboard(t *testing.T) {
+
+	y := testInit(t)
+
+	uCreated := testUserCreate(t, y)
+	defer testUserDetele(t, y, uCreated.ID)
+
+	uSuccessor, err := y.UserCreate(context.Background(), UserCreateTx{
+		Name: UserName{
+			First: testUserFirstName,
+			Last:  testUserLastName,
+		},
+		Nickname:     testUserSuccessorNickname,
+		Password:     testUserPassword,
+		DepartmentID: 1,
+	})
+	if err != nil {
+		t.Fatal("User create error:", err)
+	}
+	defer testUserDetele(t, y, uSuccessor.ID)
+
+	gCreated := testGroupCreate(t, y)
+	defer testGroupDetele(t, y, gCreated.ID)
+
+	testGroupMemberAdd(t, y, gCreated.ID, uCreated.ID)
+
+	dCreated := testDepartmentCreate(t, y)
+	defer testDepartmentDetele(t, y, dCreated.ID)
+
+	if _, err := y.DepartmentUpdate(context.Background(), dCreated.ID, DepartmentUpdateTx{
+		HeadID: SetString(uCreated.ID),
+	}); err != nil {
+		t.Fatal("Department update error:", err)
+	}
+
+	testUserOffboard(t, y, uCreated.ID, uSuccessor.ID, gCreated.ID, dCreated.ID)
+}
+
+func TestUserLookupCacheMiss(t *testing.T) {
+
+	y := testInit(t)
+
+	// Fill the cache before user is created
+	if _, err := y.UserGetByNickname(context.Background(), testUserNickame); IsNotFound(err) == false {
+		t.Fatal("User lookup cache miss error: not found error expected, got:", err)
+	}
+
+	uCreated := testUserCreate(t, y)
+	defer testUserDetele(t, y, uCreated.ID)
+
+	// Cache younger than min age is not reloaded
+	y.uc.updated = time.Now().Add(-usersCacheRefreshMinAge)
+
+	u, err := y.UserGetByNickname(context.Background(), testUserNickame)
+	if err != nil {
+		t.Fatal("User lookup cache miss error:", err)
+	}
+
+	if u.ID != uCreated.ID {
+		t.Fatalf("User lookup cache miss error: incorrect ID (returned: %s)", u.ID)
+	}
+
+	t.Logf("User lookup cache miss: success")
+}
+
+func TestUserContactValidate(t *testing.T) {
+
+	for _, c := range []struct {
+		contact UserContactTx
+		valid   bool
+	}{
+		{UserContactTx{Type: UserContactTypeEmail, Value: "user@example.com"}, true},
+		{UserContactTx{Type: UserContactTypeEmail, Value: "User <user@example.com>"}, false},
+		{UserContactTx{Type: UserContactTypeEmail, Value: "user@localhost"}, false},
+		{UserContactTx{Type: UserContactTypePhone, Value: "+7 (999) 123-45-67"}, true},
+		{UserContactTx{Type: UserContactTypePhone, Value: "phone"}, false},
+		{UserContactTx{Type: UserContactTypePhoneExtension, Value: "1234"}, true},
+		{UserContactTx{Type: UserContactTypePhoneExtension, Value: "12a"}, false},
+		{UserContactTx{Type: UserContactTypeSite, Value: "https://example.com"}, true},
+		{UserContactTx{Type: UserContactTypeSite, Value: "example.com"}, true},
+		{UserContactTx{Type: UserContactTypeSite, Value: "example.com/about"}, true},
+		{UserContactTx{Type: UserContactTypeSite, Value: "ftp://example.com"}, false},
+		{UserContactTx{Type: UserContactTypeSite, Value: "example com"}, false},
+		{UserContactTx{Type: UserContactTypeICQ, Value: "123456"}, true},
+		{UserContactTx{Type: UserContactTypeTwitter, Value: "@user_name"}, true},
+		{UserContactTx{Type: UserContactTypeSkype, Value: "live:user.name"}, true},
+		{UserContactTx{Type: "fax", Value: "123456"}, false},
+		{UserContactTx{Type: UserContactTypeSkype, Value: ""}, false},
+	} {
+		if err := c.contact.Validate(); (err == nil) != c.valid {
+			t.Fatalf("User contact validate error: incorrect result for %v (returned: %v)", c.contact, err)
+		}
+	}
+
+	t.Logf("User contact validate: success")
 }
 
 func testUserCreate(t *testing.T, y Ya360) UserRx {
 
-	u, err := y.UserCreate(UserCreateTx{
+	u, err := y.UserCreate(context.Background(), UserCreateTx{
 		Name: UserName{
 			First: testUserFirstName,
 			Last:  testUserLastName,
@@ -55,6 +180,7 @@ func testUserCreate(t *testing.T, y Ya360) UserRx {
 		Nickname:     testUserNickame,
 		Password:     testUserPassword,
 		DepartmentID: 1,
+		ExternalID:   testUserExternalID,
 	})
 	if err != nil {
 		t.Fatal("User create error:", err)
@@ -71,7 +197,7 @@ func testUserCreate(t *testing.T, y Ya360) UserRx {
 
 func testUserGet(t *testing.T, y Ya360, userID string) {
 
-	u, err := y.UserGet(userID)
+	u, err := y.UserGet(context.Background(), userID)
 	if err != nil {
 		t.Fatal("User get error:", err)
 	}
@@ -85,7 +211,7 @@ func testUserGet(t *testing.T, y Ya360, userID string) {
 
 func testUsersList(t *testing.T, y Ya360) {
 
-	u, err := y.UsersList(1, 1000)
+	u, err := y.UsersList(context.Background(), 1, 1000, ListOptions{})
 	if err != nil {
 		t.Fatal("Users list error:", err)
 	}
@@ -100,10 +226,26 @@ func testUsersList(t *testing.T, y Ya360) {
 	t.Fatal("Users list error: created user not found")
 }
 
+func testUsersListOptions(t *testing.T, y Ya360, userID string) {
+
+	u, err := y.UsersList(context.Background(), 1, 1000, ListOptions{
+		ExternalID: testUserExternalID,
+	})
+	if err != nil {
+		t.Fatal("Users list options error:", err)
+	}
+
+	if len(u.Users) != 1 || u.Users[0].ID != userID {
+		t.Fatalf("Users list options error: incorrect users filtered by external ID (returned: %d)", len(u.Users))
+	}
+
+	t.Logf("Users list options: success")
+}
+
 func testUserUpdate(t *testing.T, y Ya360, userID string) {
 
-	u, err := y.UserUpdate(userID, UserUpdateTx{
-		Name: UserName{
+	u, err := y.UserUpdate(context.Background(), userID, UserUpdateTx{
+		Name: &UserName{
 			First: testUserUpdatedFirstName,
 			Last:  testUserUpdatedLastName,
 		},
@@ -119,9 +261,77 @@ func testUserUpdate(t *testing.T, y Ya360, userID string) {
 	t.Logf("User update: success")
 }
 
+func testUserBlock(t *testing.T, y Ya360, userID string) {
+
+	u, err := y.UserBlock(context.Background(), userID)
+	if err != nil {
+		t.Fatal("User block error:", err)
+	}
+
+	if u.IsEnabled == true {
+		t.Fatal("User block error: user is still enabled")
+	}
+
+	t.Logf("User block: success")
+}
+
+func testUserUnblock(t *testing.T, y Ya360, userID string) {
+
+	u, err := y.UserUnblock(context.Background(), userID)
+	if err != nil {
+		t.Fatal("User unblock error:", err)
+	}
+
+	if u.IsEnabled == false {
+		t.Fatal("User unblock error: user is still disabled")
+	}
+
+	t.Logf("User unblock: success")
+}
+
+func testUserOffboard(t *testing.T, y Ya360, userID, successorID string, groupID, departmentID int64) {
+
+	r, err := y.UserOffboard(context.Background(), userID, UserOffboardTx{
+		HeadSuccessorID: successorID,
+	})
+	if err != nil {
+		t.Fatal("User offboard error:", err)
+	}
+
+	if len(r.Steps) != 3 {
+		t.Fatalf("User offboard error: incorrect steps (returned: %v)", r.Steps)
+	}
+
+	u, err := y.UserGet(context.Background(), userID)
+	if err != nil {
+		t.Fatal("User offboard error:", err)
+	}
+
+	if u.IsEnabled == true {
+		t.Fatal("User offboard error: user is still enabled")
+	}
+
+	for _, g := range u.Groups {
+		if g == groupID {
+			t.Fatal("User offboard error: user is still a group member")
+		}
+	}
+
+	d, err := y.DepartmentGet(context.Background(), departmentID)
+	if err != nil {
+		t.Fatal("User offboard error:", err)
+	}
+
+	if d.HeadID != successorID {
+		t.Fatalf("User offboard error: incorrect department head (returned: %s)", d.HeadID)
+	}
+
+	t.Logf("User offboard: success")
+}
+
 func testUserAliasAdd(t *testing.T, y Ya360, userID string) {
 
-	u, err := y.UserAliasAdd(userID, UserAliasAddTx{
+	u, err := y.UserAliasAdd(context.Background(), userID, UserAliasAddTx{
 		Alias: testUserAlias,
 	})
 	if err != nil {
@@ -138,9 +348,45 @@ func testUserAliasAdd(t *testing.T, y Ya360, userID string) {
 	t.Fatal("User add alias error: created alias for user not found")
 }
 
+func testUserLookup(t *testing.T, y Ya360, user UserRx) {
+
+	for _, l := range []struct {
+		name   string
+		lookup func(ctx context.Context, value string) (UserRx, error)
+		value  string
+	}{
+		{"email", y.UserGetByEmail, user.Email},
+		{"nickname", y.UserGetByNickname, testUserNickame},
+		{"external ID", y.UserGetByExternalID, testUserExternalID},
+		{"alias", y.UserFindByAlias, testUserAlias},
+	} {
+
+		u, err := l.lookup(context.Background(), l.value)
+		if err != nil {
+			t.Fatalf("User lookup by %s error: %v", l.name, err)
+		}
+
+		if u.ID != user.ID {
+			t.Fatalf("User lookup by %s error: incorrect ID (returned: %s)", l.name, u.ID)
+		}
+	}
+
+	_, err := y.UserGetByNickname(context.Background(), testUserNickame+"missing")
+	if IsNotFound(err) == false {
+		t.Fatal("User lookup error: not found error expected, got:", err)
+	}
+
+	var e UserNotFoundError
+	if errors.As(err, &e) == false || e.Field != "nickname" {
+		t.Fatal("User lookup error: incorrect not found error:", err)
+	}
+
+	t.Logf("User lookup: success")
+}
+
 func testUserAliasDelete(t *testing.T, y Ya360, userID string) {
 
-	u, err := y.UserAliasDelete(userID, testUserAlias)
+	u, err := y.UserAliasDelete(context.Background(), userID, testUserAlias)
 	if err != nil {
 		t.Fatal("User delete alias error:", err)
 	}
@@ -152,6 +398,205 @@ func testUserAliasDelete(t *testing.T, y Ya360, userID string) {
 	t.Logf("User delete alias: success")
 }
 
+func testUser2FAGet(t *testing.T, y Ya360, userID string) {
+
+	u, err := y.User2FAGet(context.Background(), userID)
+	if err != nil {
+		t.Fatal("User get 2FA error:", err)
+	}
+
+	if u.UserID != userID {
+		t.Fatal("User get 2FA error: incorrect user ID")
+	}
+
+	t.Logf("User get 2FA: success")
+}
+
+func testUsers2FAList(t *testing.T, y Ya360, userID string) {
+
+	u, err := y.Users2FAList(context.Background(), 100, 4)
+	if err != nil {
+		t.Fatal("Users 2FA list error:", err)
+	}
+
+	for _, e := range u.Users {
+		if e.UserID == userID {
+			t.Logf("Users 2FA list: success")
+			return
+		}
+	}
+
+	t.Fatal("Users 2FA list error: created user not found")
+}
+
+func testUser2FADelete(t *testing.T, y Ya360, userID string) {
+
+	u, err := y.User2FADelete(context.Background(), userID)
+	if err != nil {
+		t.Fatal("User delete 2FA error:", err)
+	}
+
+	if u.UserID != userID {
+		t.Fatal("User delete 2FA error: incorrect user ID")
+	}
+
+	s, err := y.User2FAGet(context.Background(), userID)
+	if err != nil {
+		t.Fatal("User delete 2FA error:", err)
+	}
+
+	if s.HasSecurityPhone {
+		t.Fatal("User delete 2FA error: security phone still configured")
+	}
+
+	t.Logf("User delete 2FA: success")
+}
+
+func testUserAvatarUpload(t *testing.T, y Ya360, userID string) {
+
+	a, err := y.UserAvatarUpload(context.Background(), userID, bytes.NewReader(testUserAvatar), "image/png")
+	if err != nil {
+		t.Fatal("User upload avatar error:", err)
+	}
+
+	u, err := y.UserGet(context.Background(), userID)
+	if err != nil {
+		t.Fatal("User upload avatar error:", err)
+	}
+
+	if len(a.AvatarID) == 0 || u.AvatarID != a.AvatarID {
+		t.Fatalf("User upload avatar error: incorrect avatar ID (returned: %s, user: %s)", a.AvatarID, u.AvatarID)
+	}
+
+	t.Logf("User upload avatar: success")
+}
+
+func testUserAvatarDelete(t *testing.T, y Ya360, userID string) {
+
+	if _, err := y.UserAvatarDelete(context.Background(), userID); err != nil {
+		t.Fatal("User delete avatar error:", err)
+	}
+
+	u, err := y.UserGet(context.Background(), userID)
+	if err != nil {
+		t.Fatal("User delete avatar error:", err)
+	}
+
+	if len(u.AvatarID) > 0 {
+		t.Fatalf("User delete avatar error: avatar still set (returned: %s)", u.AvatarID)
+	}
+
+	t.Logf("User delete avatar: success")
+}
+
+func testUserContactAdd(t *testing.T, y Ya360, userID string) {
+
+	u, err := y.UserContactAdd(context.Background(), userID, testUserContactPhone)
+	if err != nil {
+		t.Fatal("User add contact error:", err)
+	}
+
+	c := UserContactsEditable(u.Contacts)
+	if len(c) != 1 || c[0] != testUserContactPhone {
+		t.Fatalf("User add contact error: incorrect contacts (returned: %v)", u.Contacts)
+	}
+
+	for _, e := range u.Contacts {
+		if e.Synthetic && e.Main && e.Value == u.Email {
+			t.Logf("User add contact: success")
+			return
+		}
+	}
+
+	t.Fatal("User add contact error: synthetic contact not found")
+}
+
+func testUserContactRemove(t *testing.T, y Ya360, userID string) {
+
+	u, err := y.UserContactRemove(context.Background(), userID, testUserContactPhone)
+	if err != nil {
+		t.Fatal("User remove contact error:", err)
+	}
+
+	if c := UserContactsEditable(u.Contacts); len(c) > 0 {
+		t.Fatalf("User remove contact error: incorrect contacts (returned: %v)", c)
+	}
+
+	t.Logf("User remove contact: success")
+}
+
+func testUserContactsUpdate(t *testing.T, y Ya360, userID string) {
+
+	_, err := y.UserContactsUpdate(context.Background(), userID, []UserContactTx{{Type: UserContactTypePhone, Value: "phone"}})
+	if err == nil {
+		t.Fatal("User update contacts error: validation error expected")
+	}
+
+	u, err := y.UserContactsUpdate(context.Background(), userID, []UserContactTx{testUserContactPhone, testUserContactSite})
+	if err != nil {
+		t.Fatal("User update contacts error:", err)
+	}
+
+	if c := UserContactsEditable(u.Contacts); len(c) != 2 {
+		t.Fatalf("User update contacts error: incorrect contacts (returned: %v)", c)
+	}
+
+	t.Logf("User update contacts: success")
+}
+
+func testUserContactsDelete(t *testing.T, y Ya360, userID string) {
+
+	u, err := y.UserContactsDelete(context.Background(), userID)
+	if err != nil {
+		t.Fatal("User delete contacts error:", err)
+	}
+
+	if c := UserContactsEditable(u.Contacts); len(c) > 0 {
+		t.Fatalf("User delete contacts error: incorrect contacts (returned: %v)", c)
+	}
+
+	t.Logf("User delete contacts: success")
+}
+
+func testUserContactsKeepExisting(t *testing.T, y Ya360, userID string) {
+
+	// Contact accepted by Yandex 360 but not passing local validation
+	existing := UserContactTx{
+		Type:  UserContactTypeSkype,
+		Value: "ab",
+	}
+
+	if _, err := y.UserUpdate(context.Background(), userID, UserUpdateTx{
+		Contacts: &[]UserContactTx{existing},
+	}); err != nil {
+		t.Fatal("User keep contacts error:", err)
+	}
+
+	u, err := y.UserContactAdd(context.Background(), userID, testUserContactPhone)
+	if err != nil {
+		t.Fatal("User keep contacts error:", err)
+	}
+
+	if c := UserContactsEditable(u.Contacts); len(c) != 2 || c[0] != existing {
+		t.Fatalf("User keep contacts error: incorrect contacts (returned: %v)", c)
+	}
+
+	u, err = y.UserContactRemove(context.Background(), userID, testUserContactPhone)
+	if err != nil {
+		t.Fatal("User keep contacts error:", err)
+	}
+
+	if c := UserContactsEditable(u.Contacts); len(c) != 1 || c[0] != existing {
+		t.Fatalf("User keep contacts error: incorrect contacts (returned: %v)", c)
+	}
+
+	if _, err := y.UserContactsDelete(context.Background(), userID); err != nil {
+		t.Fatal("User keep contacts error:", err)
+	}
+
+	t.Logf("User keep contacts: success")
+}
+
 func testUserDetele(t *testing.T, y Ya360, userID string) {
 	t.Logf("User must be deleted manually. Not implemented yet in Yandex 360")
 }
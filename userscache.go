@@ -0,0 +1,94 @@
+package ya360
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// UsersCacheTTLDefault is a default lifetime of the users cache
+// used by lookup functions (e.g. `UserGetByEmail`)
+const UsersCacheTTLDefault = 5 * time.Minute
+
+// usersCachePerPage is a page size used to fill the users cache
+const usersCachePerPage = 1000
+
+// usersCacheRefreshMinAge is a minimum age of the users cache
+// to be reloaded by lookup function missed the user
+const usersCacheRefreshMinAge = 10 * time.Second
+
+// usersCache contains all organization users shared
+// by all copies of the Ya360 value returned by `Init`
+type usersCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	users   []UserRx
+	updated time.Time
+}
+
+func usersCacheInit(ttl time.Duration) *usersCache {
+
+	if ttl < 0 {
+		return nil
+	}
+
+	if ttl == 0 {
+		ttl = UsersCacheTTLDefault
+	}
+
+	return &usersCache{
+		ttl: ttl,
+	}
+}
+
+// get returns cached users or loads them if cache is expired. If `stale` is set,
+// users are reloaded as well unless the cache has been updated after `stale` or
+// is younger than `usersCacheRefreshMinAge`. Besides the users it returns time
+// they have been loaded at and whether they have been loaded by this call.
+// Concurrent callers wait for a single load
+func (c *usersCache) get(ctx context.Context, stale time.Time, load func(ctx context.Context) ([]UserRx, error)) ([]UserRx, time.Time, bool, error) {
+
+	if c == nil {
+		users, err := load(ctx)
+		return users, time.Now(), true, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.users != nil && time.Since(c.updated) < c.ttl {
+		if stale.IsZero() || c.updated.After(stale) || time.Since(c.updated) < usersCacheRefreshMinAge {
+			return c.users, c.updated, false, nil
+		}
+	}
+
+	users, err := load(ctx)
+	if err != nil {
+		return nil, time.Time{}, false, err
+	}
+
+	c.users = users
+	c.updated = time.Now()
+
+	return users, c.updated, true, nil
+}
+
+// reset drops cached users
+func (c *usersCache) reset() {
+
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.users = nil
+}
+
+// UsersCacheReset drops users cached by lookup functions,
+// so the next lookup gets the actual users list
+func (ya *Ya360) UsersCacheReset() {
+	ya.uc.reset()
+}
@@ -1,10 +1,19 @@
 package ya360
 
-import "fmt"
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
 
 // Ya360 contains Yandex 360 parameters
 type Ya360 struct {
-	s Settings
+	s  Settings
+	c  *http.Client
+	l  *rateLimiter
+	t  TokenSource
+	sa *serviceAppTokens
+	uc *usersCache
 }
 
 // Settings contain settings for node connections
@@ -12,26 +21,37 @@ type Settings struct {
 	URL   string
 	OAuth string
 	OrgID int64
-}
 
-type MemberIDType struct {
-	ID   string     `json:"id"`
-	Type MemberType `json:"type"`
-}
+	// TokenSource provides OAuth tokens for requests.
+	// If not set, static `OAuth` token is used
+	TokenSource TokenSource
 
-type Error struct {
-	Code int
-	Text string
-}
+	// ServiceApp contains service application credentials
+	// to make requests on behalf of users (see `AsUser`)
+	ServiceApp ServiceApp
+
+	// HTTPClient is used to make all requests to Yandex 360.
+	// If not set, a client with the specified `Transport` is used
+	HTTPClient *http.Client
+
+	// Transport is used to make requests if `HTTPClient` is not set.
+	// If both are not set, `http.DefaultClient` is used
+	Transport http.RoundTripper
+
+	// Retry contains policy to retry failed requests
+	Retry RetryPolicy
+
+	// RateLimit contains settings of the client side rate limiter
+	RateLimit RateLimit
 
-type errorRx struct {
-	Code    int              `json:"code"`
-	Details []errorRxDetails `json:"details"`
-	Message string           `json:"message"`
+	// UsersCacheTTL is a lifetime of the users cache used by lookup functions.
+	// If not set, `UsersCacheTTLDefault` is used. Negative value disables the cache
+	UsersCacheTTL time.Duration
 }
 
-type errorRxDetails struct {
-	Type string `json:"@type"`
+type MemberIDType struct {
+	ID   string     `json:"id"`
+	Type MemberType `json:"type"`
 }
 
 type MemberType string
@@ -57,6 +77,51 @@ func (d Order) String() string {
 	return string(d)
 }
 
+type Direction string
+
+const (
+	DirectionAsc  Direction = "asc"
+	DirectionDesc Direction = "desc"
+)
+
+func (d Direction) String() string {
+	return string(d)
+}
+
+// ListOptions contains options of users, groups and departments list requests.
+// Only the set options are sent, so zero value makes request with server defaults
+type ListOptions struct {
+
+	// ParentID filters departments by the parent department ID.
+	// Nil value means no filter. Ignored by users and groups lists
+	ParentID *int64
+
+	// OrderBy is a field to sort list by
+	OrderBy Order
+
+	// Direction is a sort direction
+	Direction Direction
+
+	// ExternalID filters list by the external ID
+	ExternalID string
+}
+
+// urlParams adds set options common for all lists to the request URL parameters
+func (o ListOptions) urlParams(v url.Values) {
+
+	if len(o.OrderBy) > 0 {
+		v.Add("orderBy", o.OrderBy.String())
+	}
+
+	if len(o.Direction) > 0 {
+		v.Add("direction", o.Direction.String())
+	}
+
+	if len(o.ExternalID) > 0 {
+		v.Add("externalId", o.ExternalID)
+	}
+}
+
 const YaHostDefault = "https://api360.yandex.net"
 
 // Init returns parametrized Node object
@@ -66,11 +131,28 @@ func Init(s Settings) Ya360 {
 		s.URL = YaHostDefault
 	}
 
-	return Ya360{
-		s: s,
+	c := s.HTTPClient
+	if c == nil {
+		if s.Transport != nil {
+			c = &http.Client{
+				Transport: s.Transport,
+			}
+		} else {
+			c = http.DefaultClient
+		}
+	}
+
+	t := s.TokenSource
+	if t == nil {
+		t = StaticToken(s.OAuth)
 	}
-}
 
-func (e Error) Error() string {
-	return fmt.Sprintf("%d, %s", e.Code, e.Text)
+	return Ya360{
+		s:  s,
+		c:  c,
+		l:  rateLimiterInit(s.RateLimit),
+		t:  t,
+		sa: serviceAppTokensInit(s.ServiceApp, c),
+		uc: usersCacheInit(s.UsersCacheTTL),
+	}
 }
@@ -0,0 +1,45 @@
+package ya360
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/nixys/nxs-go-ya360/ya360test"
+)
+
+// testInit inits Yandex 360 ctx for tests. If environment variables `YA360_OAUTH`
+// and `YA360_ORG_ID` are not defined, the fake Yandex 360 server is used
+func testInit(t *testing.T) Ya360 {
+
+	if testFake() {
+
+		fs := ya360test.Init(ya360test.Settings{})
+		t.Cleanup(fs.Close)
+
+		return Init(Settings{
+			URL:   fs.URL,
+			OAuth: fs.OAuth,
+			OrgID: fs.OrgID,
+		})
+	}
+
+	oAuth := os.Getenv("YA360_OAUTH")
+	orgID, err := strconv.ParseInt(os.Getenv("YA360_ORG_ID"), 10, 64)
+	if err != nil {
+		t.Fatal("Init error: make sure environment variable `YA360_ORG_ID` correctly defined:", err)
+	}
+	if len(oAuth) == 0 {
+		t.Fatal("Init error: make sure environment variable `YA360_OAUTH` correctly defined")
+	}
+
+	return Init(Settings{
+		OAuth: oAuth,
+		OrgID: orgID,
+	})
+}
+
+// testFake checks whether tests are running against the fake server
+func testFake() bool {
+	return len(os.Getenv("YA360_OAUTH")) == 0 && len(os.Getenv("YA360_ORG_ID")) == 0
+}
@@ -0,0 +1,315 @@
+package ya360test
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+type department struct {
+	Aliases      []string `json:"aliases"`
+	CreatedAt    string   `json:"createdAt"`
+	Description  string   `json:"description"`
+	Email        string   `json:"email"`
+	ExternalID   string   `json:"externalId"`
+	HeadID       string   `json:"headId"`
+	ID           int64    `json:"id"`
+	Label        string   `json:"label"`
+	MembersCount int64    `json:"membersCount"`
+	Name         string   `json:"name"`
+	ParentID     int64    `json:"parentId"`
+}
+
+type departmentsTx struct {
+	Departments []department `json:"departments"`
+	page
+}
+
+type departmentDeleteTx struct {
+	ID      int64 `json:"id"`
+	Removed bool  `json:"removed"`
+}
+
+var departmentPatchFields = []string{
+	"description",
+	"externalId",
+	"headId",
+	"label",
+	"name",
+	"parentId",
+}
+
+func (fs *Server) departmentsList(r *http.Request, path []string) (interface{}, *apiError) {
+
+	var (
+		parentID    int64
+		parentIDSet bool
+	)
+
+	if v := r.URL.Query().Get("parentId"); len(v) > 0 {
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, errInvalid("parentId", "parentId must be a number")
+		}
+		parentID = i
+		parentIDSet = true
+	}
+
+	o, e := listParams(r)
+	if e != nil {
+		return nil, e
+	}
+
+	deps := []*department{}
+	for _, d := range fs.departments {
+		if (parentIDSet == false || d.ParentID == parentID) && o.match(d.ExternalID) {
+			deps = append(deps, d)
+		}
+	}
+
+	sort.Slice(deps, func(i, j int) bool {
+		return o.less(deps[i].ID, deps[j].ID, deps[i].Name, deps[j].Name)
+	})
+
+	p, from, to, e := paginate(r, len(deps))
+	if e != nil {
+		return nil, e
+	}
+
+	tx := departmentsTx{
+		Departments: []department{},
+		page:        p,
+	}
+
+	for _, d := range deps[from:to] {
+		tx.Departments = append(tx.Departments, fs.departmentOut(d))
+	}
+
+	return tx, nil
+}
+
+func (fs *Server) departmentCreate(r *http.Request, path []string) (interface{}, *apiError) {
+
+	d := department{}
+	if e := decode(r, &d); e != nil {
+		return nil, e
+	}
+
+	if len(d.Name) == 0 {
+		return nil, errInvalid("name", "name is required")
+	}
+
+	if _, ok := fs.departments[d.ParentID]; ok == false {
+		return nil, errInvalid("parentId", "parent department not found")
+	}
+
+	if len(d.HeadID) > 0 {
+		if _, ok := fs.users[d.HeadID]; ok == false {
+			return nil, errInvalid("headId", "user not found")
+		}
+	}
+
+	if len(d.Label) > 0 && fs.labelUsed(d.Label) {
+		return nil, errConflict("label is already used")
+	}
+
+	fs.departmentID++
+
+	d.ID = fs.departmentID
+	d.Aliases = []string{}
+	d.CreatedAt = now()
+
+	fs.departments[d.ID] = &d
+
+	return fs.departmentOut(&d), nil
+}
+
+func (fs *Server) departmentGet(r *http.Request, path []string) (interface{}, *apiError) {
+
+	d, e := fs.departmentLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	return fs.departmentOut(d), nil
+}
+
+func (fs *Server) departmentUpdate(r *http.Request, path []string) (interface{}, *apiError) {
+
+	d, e := fs.departmentLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	n := *d
+	if e := patch(r, &n, departmentPatchFields...); e != nil {
+		return nil, e
+	}
+
+	if len(n.Name) == 0 {
+		return nil, errInvalid("name", "name is required")
+	}
+
+	if n.ParentID != d.ParentID {
+
+		if d.ID == RootDepartmentID {
+			return nil, errInvalid("parentId", "root department can't be moved")
+		}
+
+		if _, ok := fs.departments[n.ParentID]; ok == false {
+			return nil, errInvalid("parentId", "parent department not found")
+		}
+
+		// Department can't be moved into its own subtree
+		for p := n.ParentID; p != 0; p = fs.departments[p].ParentID {
+			if p == d.ID {
+				return nil, errInvalid("parentId", "department can't be moved into its own subtree")
+			}
+		}
+	}
+
+	if len(n.HeadID) > 0 {
+		if _, ok := fs.users[n.HeadID]; ok == false {
+			return nil, errInvalid("headId", "user not found")
+		}
+	}
+
+	if n.Label != d.Label && len(n.Label) > 0 && fs.labelUsed(n.Label) {
+		return nil, errConflict("label is already used")
+	}
+
+	*d = n
+
+	return fs.departmentOut(d), nil
+}
+
+func (fs *Server) departmentDelete(r *http.Request, path []string) (interface{}, *apiError) {
+
+	d, e := fs.departmentLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	if d.ID == RootDepartmentID {
+		return nil, errPrecondition("root department can't be deleted")
+	}
+
+	for _, o := range fs.departments {
+		if o.ParentID == d.ID {
+			return nil, errPrecondition("department has child departments")
+		}
+	}
+
+	if fs.departmentMembersCount(d.ID) > 0 {
+		return nil, errPrecondition("department has users")
+	}
+
+	delete(fs.departments, d.ID)
+
+	// Remove deleted department from groups
+	for _, g := range fs.groups {
+		g.Members = membersDelete(g.Members, memberTypeDepartment, path[1])
+	}
+
+	return departmentDeleteTx{
+		ID:      d.ID,
+		Removed: true,
+	}, nil
+}
+
+func (fs *Server) departmentAliasAdd(r *http.Request, path []string) (interface{}, *apiError) {
+
+	d, e := fs.departmentLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	rx := aliasRx{}
+	if e := decode(r, &rx); e != nil {
+		return nil, e
+	}
+
+	if len(rx.Alias) == 0 {
+		return nil, errInvalid("alias", "alias is required")
+	}
+
+	if len(d.Label) == 0 {
+		return nil, errPrecondition("department has no label")
+	}
+
+	if fs.labelUsed(rx.Alias) {
+		return nil, errConflict("alias is already used")
+	}
+
+	d.Aliases = append(d.Aliases, rx.Alias)
+
+	return fs.departmentOut(d), nil
+}
+
+func (fs *Server) departmentAliasDelete(r *http.Request, path []string) (interface{}, *apiError) {
+
+	d, e := fs.departmentLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	a, removed := stringsDelete(d.Aliases, path[3])
+	if removed == false {
+		return nil, errNotFound("alias")
+	}
+	d.Aliases = a
+
+	return aliasDeleteTx{
+		Alias:   path[3],
+		Removed: true,
+	}, nil
+}
+
+func (fs *Server) departmentLookup(id string) (*department, *apiError) {
+
+	i, e := pathInt64(id, "department")
+	if e != nil {
+		return nil, e
+	}
+
+	d, ok := fs.departments[i]
+	if ok == false {
+		return nil, errNotFound("department")
+	}
+
+	return d, nil
+}
+
+// departmentOut returns department with filled calculated fields
+func (fs *Server) departmentOut(d *department) department {
+
+	o := *d
+
+	o.MembersCount = fs.departmentMembersCount(d.ID)
+	if len(d.Label) > 0 {
+		o.Email = d.Label + "@" + fs.Domain
+	}
+
+	return o
+}
+
+// departmentMembersCount returns count of users in the department
+// including users of all nested departments
+func (fs *Server) departmentMembersCount(id int64) int64 {
+
+	var c int64
+
+	for _, u := range fs.users {
+		for d := fs.departments[u.DepartmentID]; d != nil; d = fs.departments[d.ParentID] {
+			if d.ID == id {
+				c++
+				break
+			}
+			if d.ParentID == 0 {
+				break
+			}
+		}
+	}
+
+	return c
+}
@@ -0,0 +1,575 @@
+package ya360test
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+const (
+	memberTypeUser       = "user"
+	memberTypeGroup      = "group"
+	memberTypeDepartment = "department"
+)
+
+type group struct {
+	AdminIDs     []string `json:"adminIds"`
+	Aliases      []string `json:"aliases"`
+	AuthorID     string   `json:"authorId"`
+	CreatedAt    string   `json:"createdAt"`
+	Description  string   `json:"description"`
+	Email        string   `json:"email"`
+	ExternalID   string   `json:"externalId"`
+	ID           int64    `json:"id"`
+	Label        string   `json:"label"`
+	MemberOf     []int64  `json:"memberOf"`
+	Members      []member `json:"members"`
+	MembersCount int64    `json:"membersCount"`
+	Name         string   `json:"name"`
+	Removed      bool     `json:"removed"`
+	Type         string   `json:"type"`
+}
+
+type member struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type groupsTx struct {
+	Groups []group `json:"groups"`
+	page
+}
+
+type groupDeleteTx struct {
+	ID      int64 `json:"id"`
+	Removed bool  `json:"removed"`
+}
+
+type groupMemberAddTx struct {
+	Added bool   `json:"added"`
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+}
+
+type groupMemberDeleteTx struct {
+	Deleted bool   `json:"deleted"`
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+}
+
+type groupMembersDeleteAllTx struct {
+	Departments []groupMemberIntIDTx `json:"departments"`
+	Groups      []groupMemberIntIDTx `json:"groups"`
+	Users       []groupMemberStrIDTx `json:"users"`
+}
+
+type groupMemberIntIDTx struct {
+	ID int64 `json:"id"`
+}
+
+type groupMemberStrIDTx struct {
+	ID string `json:"id"`
+}
+
+type groupAdminsTx struct {
+	AdminIDs []string `json:"adminIds"`
+}
+
+type groupAdminsUpdateTx struct {
+	AdminIDs []string `json:"adminIds"`
+	GroupID  int64    `json:"groupId"`
+}
+
+type groupAdminsDeleteTx struct {
+	Deleted bool  `json:"deleted"`
+	GroupID int64 `json:"groupId"`
+}
+
+type groupMembersTx struct {
+	Departments []groupMemberDepartmentTx `json:"departments"`
+	Groups      []groupMemberGroupTx      `json:"groups"`
+	Users       []groupMemberUserTx       `json:"users"`
+}
+
+type groupMemberDepartmentTx struct {
+	ID           int64  `json:"id"`
+	MembersCount int64  `json:"membersCount"`
+	Name         string `json:"name"`
+}
+
+type groupMemberGroupTx struct {
+	ID           int64  `json:"id"`
+	MembersCount int64  `json:"membersCount"`
+	Name         string `json:"name"`
+}
+
+type groupMemberUserTx struct {
+	AvatarID     string   `json:"avatarId"`
+	DepartmentID int64    `json:"departmentId"`
+	Email        string   `json:"email"`
+	Gender       string   `json:"gender"`
+	ID           string   `json:"id"`
+	Name         userName `json:"name"`
+	Nickname     string   `json:"nickname"`
+	Position     string   `json:"position"`
+}
+
+var groupPatchFields = []string{
+	"adminIds",
+	"description",
+	"externalId",
+	"label",
+	"members",
+	"name",
+}
+
+func (fs *Server) groupsList(r *http.Request, path []string) (interface{}, *apiError) {
+
+	o, e := listParams(r)
+	if e != nil {
+		return nil, e
+	}
+
+	ids := []int64{}
+	for _, id := range fs.groupIDs() {
+		if o.match(fs.groups[id].ExternalID) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return o.less(ids[i], ids[j], fs.groups[ids[i]].Name, fs.groups[ids[j]].Name)
+	})
+
+	p, from, to, e := paginate(r, len(ids))
+	if e != nil {
+		return nil, e
+	}
+
+	tx := groupsTx{
+		Groups: []group{},
+		page:   p,
+	}
+
+	for _, id := range ids[from:to] {
+		tx.Groups = append(tx.Groups, fs.groupOut(fs.groups[id]))
+	}
+
+	return tx, nil
+}
+
+func (fs *Server) groupCreate(r *http.Request, path []string) (interface{}, *apiError) {
+
+	g := group{}
+	if e := decode(r, &g); e != nil {
+		return nil, e
+	}
+
+	if len(g.Name) == 0 {
+		return nil, errInvalid("name", "name is required")
+	}
+
+	if len(g.Label) > 0 && fs.labelUsed(g.Label) {
+		return nil, errConflict("label is already used")
+	}
+
+	if e := fs.membersCheck(0, g.Members); e != nil {
+		return nil, e
+	}
+
+	if e := fs.adminsCheck(g.AdminIDs); e != nil {
+		return nil, e
+	}
+
+	fs.groupID++
+
+	g.ID = fs.groupID
+	g.Aliases = []string{}
+	g.Type = "generic"
+	g.Removed = false
+	g.CreatedAt = now()
+
+	if g.AdminIDs == nil {
+		g.AdminIDs = []string{}
+	}
+
+	if g.Members == nil {
+		g.Members = []member{}
+	}
+
+	fs.groups[g.ID] = &g
+
+	return fs.groupOut(&g), nil
+}
+
+func (fs *Server) groupGet(r *http.Request, path []string) (interface{}, *apiError) {
+
+	g, e := fs.groupLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	return fs.groupOut(g), nil
+}
+
+func (fs *Server) groupUpdate(r *http.Request, path []string) (interface{}, *apiError) {
+
+	g, e := fs.groupLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	n := *g
+	if e := patch(r, &n, groupPatchFields...); e != nil {
+		return nil, e
+	}
+
+	if len(n.Name) == 0 {
+		return nil, errInvalid("name", "name is required")
+	}
+
+	if n.Label != g.Label && len(n.Label) > 0 && fs.labelUsed(n.Label) {
+		return nil, errConflict("label is already used")
+	}
+
+	if e := fs.membersCheck(g.ID, n.Members); e != nil {
+		return nil, e
+	}
+
+	if e := fs.adminsCheck(n.AdminIDs); e != nil {
+		return nil, e
+	}
+
+	if n.Members == nil {
+		n.Members = []member{}
+	}
+
+	if n.AdminIDs == nil {
+		n.AdminIDs = []string{}
+	}
+
+	*g = n
+
+	return fs.groupOut(g), nil
+}
+
+func (fs *Server) groupDelete(r *http.Request, path []string) (interface{}, *apiError) {
+
+	g, e := fs.groupLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	delete(fs.groups, g.ID)
+
+	// Remove deleted group from other groups
+	for _, o := range fs.groups {
+		o.Members = membersDelete(o.Members, memberTypeGroup, path[1])
+	}
+
+	return groupDeleteTx{
+		ID:      g.ID,
+		Removed: true,
+	}, nil
+}
+
+func (fs *Server) groupMembersList(r *http.Request, path []string) (interface{}, *apiError) {
+
+	g, e := fs.groupLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	tx := groupMembersTx{
+		Departments: []groupMemberDepartmentTx{},
+		Groups:      []groupMemberGroupTx{},
+		Users:       []groupMemberUserTx{},
+	}
+
+	for _, m := range g.Members {
+		switch m.Type {
+		case memberTypeUser:
+			u := fs.users[m.ID]
+			tx.Users = append(tx.Users, groupMemberUserTx{
+				AvatarID:     u.AvatarID,
+				DepartmentID: u.DepartmentID,
+				Email:        u.Email,
+				Gender:       u.Gender,
+				ID:           u.ID,
+				Name:         u.Name,
+				Nickname:     u.Nickname,
+				Position:     u.Position,
+			})
+		case memberTypeGroup:
+			o, _ := fs.groupLookup(m.ID)
+			tx.Groups = append(tx.Groups, groupMemberGroupTx{
+				ID:           o.ID,
+				MembersCount: int64(len(o.Members)),
+				Name:         o.Name,
+			})
+		case memberTypeDepartment:
+			d, _ := fs.departmentLookup(m.ID)
+			tx.Departments = append(tx.Departments, groupMemberDepartmentTx{
+				ID:           d.ID,
+				MembersCount: fs.departmentMembersCount(d.ID),
+				Name:         d.Name,
+			})
+		}
+	}
+
+	return tx, nil
+}
+
+func (fs *Server) groupMemberAdd(r *http.Request, path []string) (interface{}, *apiError) {
+
+	g, e := fs.groupLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	m := member{}
+	if e := decode(r, &m); e != nil {
+		return nil, e
+	}
+
+	if len(m.Type) == 0 {
+		m.Type = memberTypeUser
+	}
+
+	if e := fs.membersCheck(g.ID, []member{m}); e != nil {
+		return nil, e
+	}
+
+	added := true
+	for _, o := range g.Members {
+		if o == m {
+			added = false
+		}
+	}
+
+	if added {
+		g.Members = append(g.Members, m)
+	}
+
+	return groupMemberAddTx{
+		Added: added,
+		ID:    m.ID,
+		Type:  m.Type,
+	}, nil
+}
+
+func (fs *Server) groupMemberDelete(r *http.Request, path []string) (interface{}, *apiError) {
+
+	g, e := fs.groupLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	n := membersDelete(g.Members, path[3], path[4])
+	if len(n) == len(g.Members) {
+		return nil, errNotFound("member")
+	}
+	g.Members = n
+
+	return groupMemberDeleteTx{
+		Deleted: true,
+		ID:      path[4],
+		Type:    path[3],
+	}, nil
+}
+
+func (fs *Server) groupMembersDeleteAll(r *http.Request, path []string) (interface{}, *apiError) {
+
+	g, e := fs.groupLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	tx := groupMembersDeleteAllTx{
+		Departments: []groupMemberIntIDTx{},
+		Groups:      []groupMemberIntIDTx{},
+		Users:       []groupMemberStrIDTx{},
+	}
+
+	for _, m := range g.Members {
+		switch m.Type {
+		case memberTypeUser:
+			tx.Users = append(tx.Users, groupMemberStrIDTx{
+				ID: m.ID,
+			})
+		case memberTypeGroup:
+			i, _ := strconv.ParseInt(m.ID, 10, 64)
+			tx.Groups = append(tx.Groups, groupMemberIntIDTx{
+				ID: i,
+			})
+		case memberTypeDepartment:
+			i, _ := strconv.ParseInt(m.ID, 10, 64)
+			tx.Departments = append(tx.Departments, groupMemberIntIDTx{
+				ID: i,
+			})
+		}
+	}
+
+	g.Members = []member{}
+
+	return tx, nil
+}
+
+func (fs *Server) groupAdminsList(r *http.Request, path []string) (interface{}, *apiError) {
+
+	g, e := fs.groupLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	return groupAdminsTx{
+		AdminIDs: g.AdminIDs,
+	}, nil
+}
+
+func (fs *Server) groupAdminsUpdate(r *http.Request, path []string) (interface{}, *apiError) {
+
+	g, e := fs.groupLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	rx := groupAdminsTx{}
+	if e := decode(r, &rx); e != nil {
+		return nil, e
+	}
+
+	if rx.AdminIDs == nil {
+		return nil, errInvalid("adminIds", "adminIds is required")
+	}
+
+	if e := fs.adminsCheck(rx.AdminIDs); e != nil {
+		return nil, e
+	}
+
+	g.AdminIDs = rx.AdminIDs
+
+	return groupAdminsUpdateTx{
+		AdminIDs: g.AdminIDs,
+		GroupID:  g.ID,
+	}, nil
+}
+
+func (fs *Server) groupAdminsDelete(r *http.Request, path []string) (interface{}, *apiError) {
+
+	g, e := fs.groupLookup(path[1])
+	if e != nil {
+		return nil, e
+	}
+
+	g.AdminIDs = []string{}
+
+	return groupAdminsDeleteTx{
+		Deleted: true,
+		GroupID: g.ID,
+	}, nil
+}
+
+func (fs *Server) groupLookup(id string) (*group, *apiError) {
+
+	i, e := pathInt64(id, "group")
+	if e != nil {
+		return nil, e
+	}
+
+	g, ok := fs.groups[i]
+	if ok == false {
+		return nil, errNotFound("group")
+	}
+
+	return g, nil
+}
+
+// groupOut returns group with filled calculated fields
+func (fs *Server) groupOut(g *group) group {
+
+	o := *g
+
+	o.MembersCount = int64(len(g.Members))
+	if len(g.Label) > 0 {
+		o.Email = g.Label + "@" + fs.Domain
+	}
+
+	o.MemberOf = []int64{}
+	for _, id := range fs.groupIDs() {
+		for _, m := range fs.groups[id].Members {
+			if m.Type == memberTypeGroup && m.ID == formatInt64(g.ID) {
+				o.MemberOf = append(o.MemberOf, id)
+			}
+		}
+	}
+
+	return o
+}
+
+// groupIDs returns sorted IDs of all groups
+func (fs *Server) groupIDs() []int64 {
+
+	ids := []int64{}
+	for id := range fs.groups {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+// membersCheck checks members of the group with specified ID exist
+func (fs *Server) membersCheck(groupID int64, members []member) *apiError {
+
+	for _, m := range members {
+		switch m.Type {
+		case memberTypeUser:
+			if _, ok := fs.users[m.ID]; ok == false {
+				return errInvalid("members", "user `"+m.ID+"` not found")
+			}
+		case memberTypeGroup:
+			if _, e := fs.groupLookup(m.ID); e != nil {
+				return errInvalid("members", "group `"+m.ID+"` not found")
+			}
+			if m.ID == formatInt64(groupID) {
+				return errInvalid("members", "group can't be a member of itself")
+			}
+		case memberTypeDepartment:
+			if _, e := fs.departmentLookup(m.ID); e != nil {
+				return errInvalid("members", "department `"+m.ID+"` not found")
+			}
+		default:
+			return errInvalid("members", "unknown member type `"+m.Type+"`")
+		}
+	}
+
+	return nil
+}
+
+// adminsCheck checks group admins exist
+func (fs *Server) adminsCheck(ids []string) *apiError {
+
+	for _, id := range ids {
+		if _, ok := fs.users[id]; ok == false {
+			return errInvalid("adminIds", "user `"+id+"` not found")
+		}
+	}
+
+	return nil
+}
+
+func membersDelete(members []member, t, id string) []member {
+
+	r := []member{}
+	for _, m := range members {
+		if m.Type != t || m.ID != id {
+			r = append(r, m)
+		}
+	}
+
+	return r
+}
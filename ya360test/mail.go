@@ -0,0 +1,86 @@
+package ya360test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+type mailRoutingRules struct {
+	Rules []mailRoutingRule `json:"rules"`
+}
+
+type mailRoutingRule struct {
+	Terminal  bool                `json:"terminal"`
+	Condition json.RawMessage     `json:"condition"`
+	Actions   []mailRoutingAction `json:"actions"`
+	Scope     mailRoutingScope    `json:"scope"`
+}
+
+type mailRoutingAction struct {
+	Action string                 `json:"action"`
+	Data   *mailRoutingActionData `json:"data,omitempty"`
+}
+
+type mailRoutingActionData struct {
+	Email string `json:"email,omitempty"`
+}
+
+type mailRoutingScope struct {
+	Direction string `json:"direction"`
+}
+
+func (fs *Server) mailRoutingRulesGet(r *http.Request, path []string) (interface{}, *apiError) {
+
+	o := mailRoutingRules{
+		Rules: fs.mailRoutingRules,
+	}
+	if o.Rules == nil {
+		o.Rules = []mailRoutingRule{}
+	}
+
+	return o, nil
+}
+
+func (fs *Server) mailRoutingRulesSet(r *http.Request, path []string) (interface{}, *apiError) {
+
+	rx := mailRoutingRules{}
+	if e := decode(r, &rx); e != nil {
+		return nil, e
+	}
+
+	for i, e := range rx.Rules {
+
+		f := "rules[" + strconv.Itoa(i) + "]"
+
+		if len(e.Condition) == 0 || e.Condition[0] != '{' {
+			return nil, errInvalid(f+".condition", "condition must be an object")
+		}
+
+		switch e.Scope.Direction {
+		case "inbound", "outbound":
+		default:
+			return nil, errInvalid(f+".scope.direction", "direction must be one of `inbound` or `outbound`")
+		}
+
+		if len(e.Actions) == 0 {
+			return nil, errInvalid(f+".actions", "at least one action is required")
+		}
+
+		for _, a := range e.Actions {
+			switch a.Action {
+			case "drop":
+			case "forward", "redirect":
+				if a.Data == nil || len(a.Data.Email) == 0 {
+					return nil, errInvalid(f+".actions", "email is required for `"+a.Action+"` action")
+				}
+			default:
+				return nil, errInvalid(f+".actions", "unknown action `"+a.Action+"`")
+			}
+		}
+	}
+
+	fs.mailRoutingRules = rx.Rules
+
+	return struct{}{}, nil
+}
@@ -0,0 +1,79 @@
+package ya360test
+
+import (
+	"net/http"
+)
+
+type domain2FA struct {
+	Enabled   bool           `json:"enabled"`
+	EnabledAt string         `json:"enabledAt"`
+	Duration  int64          `json:"duration"`
+	Scope     domain2FAScope `json:"scope"`
+}
+
+type domain2FAScope struct {
+	AllUsers bool    `json:"allUsers"`
+	GroupIDs []int64 `json:"groupIds"`
+}
+
+type domain2FAEnableRx struct {
+	Duration    int64          `json:"duration"`
+	LogoutUsers bool           `json:"logoutUsers"`
+	Scope       domain2FAScope `json:"scope"`
+}
+
+func (fs *Server) domain2FAGet(r *http.Request, path []string) (interface{}, *apiError) {
+	return fs.domain2FAOut(), nil
+}
+
+func (fs *Server) domain2FAEnable(r *http.Request, path []string) (interface{}, *apiError) {
+
+	rx := domain2FAEnableRx{}
+	if e := decode(r, &rx); e != nil {
+		return nil, e
+	}
+
+	if rx.Duration < 0 {
+		return nil, errInvalid("duration", "duration must not be negative")
+	}
+
+	if rx.Scope.AllUsers == false && len(rx.Scope.GroupIDs) == 0 {
+		return nil, errInvalid("scope", "either allUsers or groupIds must be specified")
+	}
+
+	if rx.Scope.AllUsers && len(rx.Scope.GroupIDs) > 0 {
+		return nil, errInvalid("scope", "allUsers and groupIds are mutually exclusive")
+	}
+
+	for _, id := range rx.Scope.GroupIDs {
+		if _, ok := fs.groups[id]; ok == false {
+			return nil, errInvalid("scope", "group `"+formatInt64(id)+"` not found")
+		}
+	}
+
+	fs.domain2FA = domain2FA{
+		Enabled:   true,
+		EnabledAt: now(),
+		Duration:  rx.Duration,
+		Scope:     rx.Scope,
+	}
+
+	return fs.domain2FAOut(), nil
+}
+
+func (fs *Server) domain2FADisable(r *http.Request, path []string) (interface{}, *apiError) {
+
+	fs.domain2FA = domain2FA{}
+
+	return fs.domain2FAOut(), nil
+}
+
+func (fs *Server) domain2FAOut() domain2FA {
+
+	o := fs.domain2FA
+	if o.Scope.GroupIDs == nil {
+		o.Scope.GroupIDs = []int64{}
+	}
+
+	return o
+}
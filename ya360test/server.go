@@ -0,0 +1,511 @@
+// Package ya360test provides in-memory fake Yandex 360 API server
+// to test code using `github.com/nixys/nxs-go-ya360` offline.
+//
+// The fake implements directory endpoints for users, groups, departments
+// and their aliases with pagination, validation errors and IDs assignment
+// similar to Yandex 360 behaviour, as well as organization security settings
+// and mail routing rules.
+package ya360test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	OrgIDDefault  int64 = 1
+	OAuthDefault        = "test"
+	DomainDefault       = "example.com"
+
+	// RootDepartmentID is an ID of the root department existing in every organization
+	RootDepartmentID int64 = 1
+
+	perPageDefault int64 = 10
+	perPageMax     int64 = 1000
+
+	userIDBase int64 = 1130000000000000
+)
+
+// Yandex 360 API error codes
+const (
+	codeInvalidArgument    = 3
+	codeNotFound           = 5
+	codeAlreadyExists      = 6
+	codeFailedPrecondition = 9
+	codeUnauthenticated    = 16
+)
+
+// Settings contain settings of the fake server
+type Settings struct {
+
+	// OrgID is an organization ID served by the fake.
+	// If not set, `OrgIDDefault` is used
+	OrgID int64
+
+	// OAuth is a token accepted by the fake. Requests with other
+	// tokens are rejected. If not set, `OAuthDefault` is used
+	OAuth string
+
+	// Domain is an organization domain used for emails.
+	// If not set, `DomainDefault` is used
+	Domain string
+}
+
+// Server is a fake Yandex 360 API server
+type Server struct {
+	URL    string
+	OrgID  int64
+	OAuth  string
+	Domain string
+
+	srv *httptest.Server
+
+	mu          sync.Mutex
+	users       map[string]*user
+	groups      map[int64]*group
+	departments map[int64]*department
+	domain2FA   domain2FA
+
+	mailRoutingRules []mailRoutingRule
+
+	userID       int64
+	groupID      int64
+	departmentID int64
+	avatarID     int64
+}
+
+type errorTx struct {
+	Code    int                      `json:"code"`
+	Message string                   `json:"message"`
+	Details []map[string]interface{} `json:"details"`
+}
+
+// apiError is an error returned by handlers
+type apiError struct {
+	status  int
+	code    int
+	message string
+	field   string
+}
+
+type handlerFunc func(r *http.Request, path []string) (interface{}, *apiError)
+
+// Init starts a new fake server. Server must be closed with `Close` after use
+func Init(s Settings) *Server {
+
+	if s.OrgID == 0 {
+		s.OrgID = OrgIDDefault
+	}
+
+	if len(s.OAuth) == 0 {
+		s.OAuth = OAuthDefault
+	}
+
+	if len(s.Domain) == 0 {
+		s.Domain = DomainDefault
+	}
+
+	fs := &Server{
+		OrgID:        s.OrgID,
+		OAuth:        s.OAuth,
+		Domain:       s.Domain,
+		users:        make(map[string]*user),
+		groups:       make(map[int64]*group),
+		departments:  make(map[int64]*department),
+		userID:       userIDBase,
+		departmentID: RootDepartmentID,
+	}
+
+	fs.departments[RootDepartmentID] = &department{
+		ID:        RootDepartmentID,
+		Name:      "All employees",
+		Aliases:   []string{},
+		CreatedAt: now(),
+	}
+
+	fs.srv = httptest.NewServer(http.HandlerFunc(fs.serveHTTP))
+	fs.URL = fs.srv.URL
+
+	return fs
+}
+
+// Close shuts down the server
+func (fs *Server) Close() {
+	fs.srv.Close()
+}
+
+func (fs *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Header.Get("Authorization") != "OAuth "+fs.OAuth {
+		writeError(w, &apiError{
+			status:  http.StatusUnauthorized,
+			code:    codeUnauthenticated,
+			message: "invalid oauth token",
+		})
+		return
+	}
+
+	h, path := fs.route(r)
+	if h == nil {
+		writeError(w, errNotFound("method"))
+		return
+	}
+
+	fs.mu.Lock()
+	resp, e := h(r, path)
+	fs.mu.Unlock()
+
+	if e != nil {
+		writeError(w, e)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// route returns handler for the request and request path split into
+// segments after the organization prefix (e.g. `/directory/v1/org/1/`)
+func (fs *Server) route(r *http.Request) (handlerFunc, []string) {
+
+	// Path format: /{api}/v1/org/{orgId}/{path}
+	s := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 5)
+	if len(s) < 5 || s[1] != "v1" || s[2] != "org" || s[3] != formatInt64(fs.OrgID) {
+		return nil, nil
+	}
+
+	api := s[0]
+	path := strings.Split(strings.TrimSuffix(s[4], "/"), "/")
+
+	type route struct {
+		method string
+		path   string
+		h      handlerFunc
+	}
+
+	routes := []route{
+		{http.MethodGet, "directory/users", fs.usersList},
+		{http.MethodPost, "directory/users", fs.userCreate},
+		{http.MethodGet, "directory/users/*", fs.userGet},
+		{http.MethodPatch, "directory/users/*", fs.userUpdate},
+		{http.MethodPost, "directory/users/*/aliases", fs.userAliasAdd},
+		{http.MethodDelete, "directory/users/*/aliases/*", fs.userAliasDelete},
+		{http.MethodGet, "directory/users/*/2fa", fs.user2FAGet},
+		{http.MethodDelete, "directory/users/*/2fa", fs.user2FADelete},
+		{http.MethodPut, "directory/users/*/avatar", fs.userAvatarUpload},
+		{http.MethodDelete, "directory/users/*/avatar", fs.userAvatarDelete},
+		{http.MethodPut, "directory/users/*/contacts", fs.userContactsUpdate},
+		{http.MethodDelete, "directory/users/*/contacts", fs.userContactsDelete},
+
+		{http.MethodGet, "directory/groups", fs.groupsList},
+		{http.MethodPost, "directory/groups", fs.groupCreate},
+		{http.MethodGet, "directory/groups/*", fs.groupGet},
+		{http.MethodPatch, "directory/groups/*", fs.groupUpdate},
+		{http.MethodDelete, "directory/groups/*", fs.groupDelete},
+		{http.MethodGet, "directory/groups/*/members", fs.groupMembersList},
+		{http.MethodPost, "directory/groups/*/members", fs.groupMemberAdd},
+		{http.MethodDelete, "directory/groups/*/members", fs.groupMembersDeleteAll},
+		{http.MethodDelete, "directory/groups/*/members/*/*", fs.groupMemberDelete},
+		{http.MethodGet, "directory/groups/*/admins", fs.groupAdminsList},
+		{http.MethodPut, "directory/groups/*/admins", fs.groupAdminsUpdate},
+		{http.MethodDelete, "directory/groups/*/admins", fs.groupAdminsDelete},
+
+		{http.MethodGet, "directory/departments", fs.departmentsList},
+		{http.MethodPost, "directory/departments", fs.departmentCreate},
+		{http.MethodGet, "directory/departments/*", fs.departmentGet},
+		{http.MethodPatch, "directory/departments/*", fs.departmentUpdate},
+		{http.MethodDelete, "directory/departments/*", fs.departmentDelete},
+		{http.MethodPost, "directory/departments/*/aliases", fs.departmentAliasAdd},
+		{http.MethodDelete, "directory/departments/*/aliases/*", fs.departmentAliasDelete},
+
+		{http.MethodGet, "security/domain_2fa", fs.domain2FAGet},
+		{http.MethodPost, "security/domain_2fa", fs.domain2FAEnable},
+		{http.MethodDelete, "security/domain_2fa", fs.domain2FADisable},
+
+		{http.MethodGet, "admin/mail/routing/rules", fs.mailRoutingRulesGet},
+		{http.MethodPut, "admin/mail/routing/rules", fs.mailRoutingRulesSet},
+	}
+
+	for _, e := range routes {
+		if e.method == r.Method && pathMatch(strings.Split(e.path, "/"), append([]string{api}, path...)) {
+			return e.h, path
+		}
+	}
+
+	return nil, nil
+}
+
+func pathMatch(pattern, path []string) bool {
+
+	if len(pattern) != len(path) {
+		return false
+	}
+
+	for i, p := range pattern {
+		if p != "*" && p != path[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func writeError(w http.ResponseWriter, e *apiError) {
+
+	tx := errorTx{
+		Code:    e.code,
+		Message: e.message,
+		Details: []map[string]interface{}{},
+	}
+
+	if len(e.field) > 0 {
+		tx.Details = append(tx.Details, map[string]interface{}{
+			"@type": "type.googleapis.com/google.rpc.BadRequest",
+			"fieldViolations": []map[string]string{
+				{
+					"field":       e.field,
+					"description": e.message,
+				},
+			},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.status)
+	json.NewEncoder(w).Encode(tx)
+}
+
+func errNotFound(object string) *apiError {
+	return &apiError{
+		status:  http.StatusNotFound,
+		code:    codeNotFound,
+		message: object + " not found",
+	}
+}
+
+func errInvalid(field, message string) *apiError {
+	return &apiError{
+		status:  http.StatusBadRequest,
+		code:    codeInvalidArgument,
+		message: message,
+		field:   field,
+	}
+}
+
+func errConflict(message string) *apiError {
+	return &apiError{
+		status:  http.StatusConflict,
+		code:    codeAlreadyExists,
+		message: message,
+	}
+}
+
+func errPrecondition(message string) *apiError {
+	return &apiError{
+		status:  http.StatusBadRequest,
+		code:    codeFailedPrecondition,
+		message: message,
+	}
+}
+
+// decode decodes request body
+func decode(r *http.Request, v interface{}) *apiError {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errInvalid("body", fmt.Sprintf("can't decode request body: %v", err))
+	}
+	return nil
+}
+
+// patch applies fields from request body to the object. Fields with
+// `null` value are reset to zero values. Fields not in `allowed` are ignored
+func patch(r *http.Request, obj interface{}, allowed ...string) *apiError {
+
+	p := make(map[string]json.RawMessage)
+	if e := decode(r, &p); e != nil {
+		return e
+	}
+
+	set := make(map[string]json.RawMessage)
+
+	for _, k := range allowed {
+		v, ok := p[k]
+		if ok == false {
+			continue
+		}
+		if string(v) == "null" {
+			fieldReset(obj, k)
+		} else {
+			set[k] = v
+		}
+	}
+
+	b, err := json.Marshal(set)
+	if err != nil {
+		return errInvalid("body", err.Error())
+	}
+
+	if err := json.Unmarshal(b, obj); err != nil {
+		return errInvalid("body", fmt.Sprintf("can't decode request body: %v", err))
+	}
+
+	return nil
+}
+
+// fieldReset sets zero value to the struct field with specified JSON name
+func fieldReset(obj interface{}, name string) {
+
+	v := reflect.ValueOf(obj).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		if strings.Split(t.Field(i).Tag.Get("json"), ",")[0] == name {
+			v.Field(i).Set(reflect.Zero(t.Field(i).Type))
+			return
+		}
+	}
+}
+
+// listOrder contains ordering and filtering parameters of list requests
+type listOrder struct {
+	byName     bool
+	desc       bool
+	externalID string
+}
+
+// listParams parses `orderBy`, `direction` and `externalId` parameters of list requests
+func listParams(r *http.Request) (listOrder, *apiError) {
+
+	o := listOrder{
+		externalID: r.URL.Query().Get("externalId"),
+	}
+
+	switch r.URL.Query().Get("orderBy") {
+	case "", "id":
+	case "name":
+		o.byName = true
+	default:
+		return o, errInvalid("orderBy", "orderBy must be one of `id` or `name`")
+	}
+
+	switch r.URL.Query().Get("direction") {
+	case "", "asc":
+	case "desc":
+		o.desc = true
+	default:
+		return o, errInvalid("direction", "direction must be one of `asc` or `desc`")
+	}
+
+	return o, nil
+}
+
+// match checks the element external ID matches the filter
+func (o listOrder) match(externalID string) bool {
+	return len(o.externalID) == 0 || o.externalID == externalID
+}
+
+// less compares two elements in accordance with requested order
+func (o listOrder) less(id1, id2 int64, name1, name2 string) bool {
+
+	if o.desc == true {
+		id1, id2 = id2, id1
+		name1, name2 = name2, name1
+	}
+
+	if o.byName == true && name1 != name2 {
+		return name1 < name2
+	}
+
+	return id1 < id2
+}
+
+// page contains pagination data for list responses
+type page struct {
+	Page    int64 `json:"page"`
+	Pages   int64 `json:"pages"`
+	PerPage int64 `json:"perPage"`
+	Total   int64 `json:"total"`
+}
+
+// paginate returns pagination data and bounds of elements for the requested page
+func paginate(r *http.Request, total int) (page, int, int, *apiError) {
+
+	p := page{
+		Page:    1,
+		PerPage: perPageDefault,
+		Total:   int64(total),
+	}
+
+	if v := r.URL.Query().Get("page"); len(v) > 0 {
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || i < 1 {
+			return p, 0, 0, errInvalid("page", "page must be a positive number")
+		}
+		p.Page = i
+	}
+
+	if v := r.URL.Query().Get("perPage"); len(v) > 0 {
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || i < 1 || i > perPageMax {
+			return p, 0, 0, errInvalid("perPage", fmt.Sprintf("perPage must be in range 1..%d", perPageMax))
+		}
+		p.PerPage = i
+	}
+
+	p.Pages = (p.Total + p.PerPage - 1) / p.PerPage
+	if p.Pages == 0 {
+		p.Pages = 1
+	}
+
+	from := (p.Page - 1) * p.PerPage
+	if from > p.Total {
+		from = p.Total
+	}
+
+	to := from + p.PerPage
+	if to > p.Total {
+		to = p.Total
+	}
+
+	return p, int(from), int(to), nil
+}
+
+func pathInt64(s, object string) (int64, *apiError) {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, errNotFound(object)
+	}
+	return i, nil
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func stringsDelete(s []string, v string) ([]string, bool) {
+	for i, e := range s {
+		if e == v {
+			return append(s[:i:i], s[i+1:]...), true
+		}
+	}
+	return s, false
+}
+
+func stringsContain(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func formatInt64(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
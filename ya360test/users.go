@@ -0,0 +1,513 @@
+package ya360test
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type user struct {
+	About        string    `json:"about"`
+	Aliases      []string  `json:"aliases"`
+	AvatarID     string    `json:"avatarId"`
+	Birthday     string    `json:"birthday"`
+	Contacts     []contact `json:"contacts"`
+	CreatedAt    string    `json:"createdAt"`
+	DepartmentID int64     `json:"departmentId"`
+	Email        string    `json:"email"`
+	ExternalID   string    `json:"externalId"`
+	Gender       string    `json:"gender"`
+	Groups       []int64   `json:"groups"`
+	ID           string    `json:"id"`
+	IsAdmin      bool      `json:"isAdmin"`
+	IsDismissed  bool      `json:"isDismissed"`
+	IsEnabled    bool      `json:"isEnabled"`
+	IsRobot      bool      `json:"isRobot"`
+	Language     string    `json:"language"`
+	Name         userName  `json:"name"`
+	Nickname     string    `json:"nickname"`
+	Position     string    `json:"position"`
+	Timezone     string    `json:"timezone"`
+	UpdatedAt    string    `json:"updatedAt"`
+
+	has2FA           bool
+	hasSecurityPhone bool
+
+	avatar            []byte
+	avatarContentType string
+}
+
+type userName struct {
+	First  string `json:"first"`
+	Last   string `json:"last"`
+	Middle string `json:"middle"`
+}
+
+type contact struct {
+	Alias     bool   `json:"alias"`
+	Main      bool   `json:"main"`
+	Synthetic bool   `json:"synthetic"`
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+}
+
+type userCreateRx struct {
+	user
+	Password string `json:"password"`
+}
+
+type user2FATx struct {
+	UserID           string `json:"userId"`
+	Has2FA           bool   `json:"has2fa"`
+	HasSecurityPhone bool   `json:"hasSecurityPhone"`
+}
+
+type user2FADeleteTx struct {
+	UserID string `json:"userId"`
+}
+
+type userAvatarTx struct {
+	AvatarID string `json:"avatarId"`
+}
+
+type userAvatarDeleteTx struct {
+	UserID  string `json:"userId"`
+	Removed bool   `json:"removed"`
+}
+
+type userContactsRx struct {
+	Contacts []contact `json:"contacts"`
+}
+
+type aliasRx struct {
+	Alias string `json:"alias"`
+}
+
+type aliasDeleteTx struct {
+	Alias   string `json:"alias"`
+	Removed bool   `json:"removed"`
+}
+
+type usersTx struct {
+	Users []user `json:"users"`
+	page
+}
+
+var contactTypes = []string{
+	"email",
+	"phone_extension",
+	"phone",
+	"site",
+	"icq",
+	"twitter",
+	"skype",
+}
+
+var userPatchFields = []string{
+	"about",
+	"birthday",
+	"contacts",
+	"departmentId",
+	"externalId",
+	"gender",
+	"isAdmin",
+	"isEnabled",
+	"language",
+	"name",
+	"nickname",
+	"password",
+	"passwordChangeRequired",
+	"position",
+	"timezone",
+}
+
+func (fs *Server) usersList(r *http.Request, path []string) (interface{}, *apiError) {
+
+	o, e := listParams(r)
+	if e != nil {
+		return nil, e
+	}
+
+	ids := []string{}
+	for id, u := range fs.users {
+		if o.match(u.ExternalID) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		ui, uj := fs.users[ids[i]], fs.users[ids[j]]
+		return o.less(userIDInt64(ui.ID), userIDInt64(uj.ID), ui.Name.Last+" "+ui.Name.First, uj.Name.Last+" "+uj.Name.First)
+	})
+
+	p, from, to, e := paginate(r, len(ids))
+	if e != nil {
+		return nil, e
+	}
+
+	tx := usersTx{
+		Users: []user{},
+		page:  p,
+	}
+
+	for _, id := range ids[from:to] {
+		tx.Users = append(tx.Users, fs.userOut(fs.users[id]))
+	}
+
+	return tx, nil
+}
+
+func (fs *Server) userCreate(r *http.Request, path []string) (interface{}, *apiError) {
+
+	rx := userCreateRx{}
+	if e := decode(r, &rx); e != nil {
+		return nil, e
+	}
+
+	if len(rx.Nickname) == 0 {
+		return nil, errInvalid("nickname", "nickname is required")
+	}
+
+	if len(rx.Password) == 0 {
+		return nil, errInvalid("password", "password is required")
+	}
+
+	if _, ok := fs.departments[rx.DepartmentID]; ok == false {
+		return nil, errInvalid("departmentId", "department not found")
+	}
+
+	if fs.labelUsed(rx.Nickname) {
+		return nil, errConflict("nickname is already used")
+	}
+
+	fs.userID++
+
+	u := rx.user
+	u.ID = strconv.FormatInt(fs.userID, 10)
+	u.Email = rx.Nickname + "@" + fs.Domain
+	u.Aliases = []string{}
+	u.IsEnabled = true
+	u.IsDismissed = false
+	u.CreatedAt = now()
+	u.UpdatedAt = u.CreatedAt
+
+	for i := range u.Contacts {
+		u.Contacts[i].Alias = false
+		u.Contacts[i].Main = false
+		u.Contacts[i].Synthetic = false
+	}
+
+	fs.users[u.ID] = &u
+
+	return fs.userOut(&u), nil
+}
+
+func (fs *Server) userGet(r *http.Request, path []string) (interface{}, *apiError) {
+
+	u, ok := fs.users[path[1]]
+	if ok == false {
+		return nil, errNotFound("user")
+	}
+
+	return fs.userOut(u), nil
+}
+
+func (fs *Server) userUpdate(r *http.Request, path []string) (interface{}, *apiError) {
+
+	u, ok := fs.users[path[1]]
+	if ok == false {
+		return nil, errNotFound("user")
+	}
+
+	n := *u
+	if e := patch(r, &n, userPatchFields...); e != nil {
+		return nil, e
+	}
+
+	if _, ok := fs.departments[n.DepartmentID]; ok == false {
+		return nil, errInvalid("departmentId", "department not found")
+	}
+
+	if n.Nickname != u.Nickname {
+		if len(n.Nickname) == 0 {
+			return nil, errInvalid("nickname", "nickname is required")
+		}
+		if fs.labelUsed(n.Nickname) {
+			return nil, errConflict("nickname is already used")
+		}
+		n.Email = n.Nickname + "@" + fs.Domain
+	}
+
+	n.UpdatedAt = now()
+	*u = n
+
+	return fs.userOut(u), nil
+}
+
+func (fs *Server) userAliasAdd(r *http.Request, path []string) (interface{}, *apiError) {
+
+	u, ok := fs.users[path[1]]
+	if ok == false {
+		return nil, errNotFound("user")
+	}
+
+	rx := aliasRx{}
+	if e := decode(r, &rx); e != nil {
+		return nil, e
+	}
+
+	if len(rx.Alias) == 0 {
+		return nil, errInvalid("alias", "alias is required")
+	}
+
+	if fs.labelUsed(rx.Alias) {
+		return nil, errConflict("alias is already used")
+	}
+
+	u.Aliases = append(u.Aliases, rx.Alias)
+
+	return fs.userOut(u), nil
+}
+
+func (fs *Server) userAliasDelete(r *http.Request, path []string) (interface{}, *apiError) {
+
+	u, ok := fs.users[path[1]]
+	if ok == false {
+		return nil, errNotFound("user")
+	}
+
+	a, removed := stringsDelete(u.Aliases, path[3])
+	if removed == false {
+		return nil, errNotFound("alias")
+	}
+	u.Aliases = a
+
+	return aliasDeleteTx{
+		Alias:   path[3],
+		Removed: true,
+	}, nil
+}
+
+func (fs *Server) user2FAGet(r *http.Request, path []string) (interface{}, *apiError) {
+
+	u, ok := fs.users[path[1]]
+	if ok == false {
+		return nil, errNotFound("user")
+	}
+
+	return user2FATx{
+		UserID:           u.ID,
+		Has2FA:           u.has2FA,
+		HasSecurityPhone: u.hasSecurityPhone,
+	}, nil
+}
+
+func (fs *Server) user2FADelete(r *http.Request, path []string) (interface{}, *apiError) {
+
+	u, ok := fs.users[path[1]]
+	if ok == false {
+		return nil, errNotFound("user")
+	}
+
+	u.has2FA = false
+	u.hasSecurityPhone = false
+
+	return user2FADeleteTx{
+		UserID: u.ID,
+	}, nil
+}
+
+func (fs *Server) userAvatarUpload(r *http.Request, path []string) (interface{}, *apiError) {
+
+	u, ok := fs.users[path[1]]
+	if ok == false {
+		return nil, errNotFound("user")
+	}
+
+	ct := r.Header.Get("Content-Type")
+	if strings.HasPrefix(ct, "image/") == false {
+		return nil, errInvalid("Content-Type", "avatar must be an image")
+	}
+
+	b, err := io.ReadAll(r.Body)
+	if err != nil || len(b) == 0 {
+		return nil, errInvalid("body", "avatar image is empty")
+	}
+
+	fs.avatarID++
+
+	u.AvatarID = strconv.FormatInt(fs.avatarID, 10) + "/" + u.ID
+	u.avatar = b
+	u.avatarContentType = ct
+
+	return userAvatarTx{
+		AvatarID: u.AvatarID,
+	}, nil
+}
+
+func (fs *Server) userAvatarDelete(r *http.Request, path []string) (interface{}, *apiError) {
+
+	u, ok := fs.users[path[1]]
+	if ok == false {
+		return nil, errNotFound("user")
+	}
+
+	removed := len(u.AvatarID) > 0
+
+	u.AvatarID = ""
+	u.avatar = nil
+	u.avatarContentType = ""
+
+	return userAvatarDeleteTx{
+		UserID:  u.ID,
+		Removed: removed,
+	}, nil
+}
+
+func (fs *Server) userContactsUpdate(r *http.Request, path []string) (interface{}, *apiError) {
+
+	u, ok := fs.users[path[1]]
+	if ok == false {
+		return nil, errNotFound("user")
+	}
+
+	rx := userContactsRx{}
+	if e := decode(r, &rx); e != nil {
+		return nil, e
+	}
+
+	c := []contact{}
+	for _, e := range rx.Contacts {
+		if stringsContain(contactTypes, e.Type) == false {
+			return nil, errInvalid("contacts", "unknown contact type `"+e.Type+"`")
+		}
+		if len(e.Value) == 0 {
+			return nil, errInvalid("contacts", "contact value is required")
+		}
+		c = append(c, contact{
+			Type:  e.Type,
+			Value: e.Value,
+		})
+	}
+
+	u.Contacts = c
+	u.UpdatedAt = now()
+
+	return fs.userOut(u), nil
+}
+
+func (fs *Server) userContactsDelete(r *http.Request, path []string) (interface{}, *apiError) {
+
+	u, ok := fs.users[path[1]]
+	if ok == false {
+		return nil, errNotFound("user")
+	}
+
+	u.Contacts = []contact{}
+	u.UpdatedAt = now()
+
+	return fs.userOut(u), nil
+}
+
+// UserAvatar returns avatar image uploaded for the user and its content type
+func (fs *Server) UserAvatar(userID string) ([]byte, string) {
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	u, ok := fs.users[userID]
+	if ok == false {
+		return nil, ""
+	}
+
+	return u.avatar, u.avatarContentType
+}
+
+// User2FASet sets two-factor authentication status for the user.
+// It returns false if user does not exist
+func (fs *Server) User2FASet(userID string, has2FA, hasSecurityPhone bool) bool {
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	u, ok := fs.users[userID]
+	if ok == false {
+		return false
+	}
+
+	u.has2FA = has2FA
+	u.hasSecurityPhone = hasSecurityPhone
+
+	return true
+}
+
+// userOut returns user with filled calculated fields
+func (fs *Server) userOut(u *user) user {
+
+	o := *u
+
+	o.Groups = []int64{}
+	for _, id := range fs.groupIDs() {
+		for _, m := range fs.groups[id].Members {
+			if m.Type == memberTypeUser && m.ID == u.ID {
+				o.Groups = append(o.Groups, id)
+			}
+		}
+	}
+
+	o.Contacts = []contact{}
+	for _, c := range u.Contacts {
+		o.Contacts = append(o.Contacts, c)
+	}
+
+	o.Contacts = append(o.Contacts, contact{
+		Main:      true,
+		Synthetic: true,
+		Type:      "email",
+		Value:     u.Email,
+	})
+
+	for _, a := range u.Aliases {
+		o.Contacts = append(o.Contacts, contact{
+			Alias:     true,
+			Synthetic: true,
+			Type:      "email",
+			Value:     a + "@" + fs.Domain,
+		})
+	}
+
+	return o
+}
+
+// userIDInt64 converts user ID to number to compare users IDs
+func userIDInt64(id string) int64 {
+	i, _ := strconv.ParseInt(id, 10, 64)
+	return i
+}
+
+// labelUsed checks whether the email local part is already used
+// by any user, group or department in the organization
+func (fs *Server) labelUsed(label string) bool {
+
+	for _, u := range fs.users {
+		if u.Nickname == label || stringsContain(u.Aliases, label) {
+			return true
+		}
+	}
+
+	for _, g := range fs.groups {
+		if g.Label == label || stringsContain(g.Aliases, label) {
+			return true
+		}
+	}
+
+	for _, d := range fs.departments {
+		if d.Label == label || stringsContain(d.Aliases, label) {
+			return true
+		}
+	}
+
+	return false
+}